@@ -11,53 +11,95 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"sort"
+	"strings"
+	"time"
 )
 
-var (
-	baseURL = "https://api.instagram.com/v1"
-)
+// DefaultBaseURL is the base URL of the Instagram API.
+const DefaultBaseURL = "https://api.instagram.com/v1"
+
+// DefaultGraphURL is the base URL of the Instagram Graph API.
+const DefaultGraphURL = "https://graph.instagram.com"
 
-// API is the Instagram API.
+// API is the Instagram API. An API holds only configuration; metadata about
+// each call is returned in that call's Response. Once configured, an API is
+// safe for concurrent use by multiple goroutines.
 type API struct {
 	ClientID             string
 	ClientSecret         string
 	AccessToken          string
 	EnforceSignedRequest bool
 
+	// TokenSource, if set, supplies the access token of each request in
+	// place of AccessToken, for instance a RefreshingTokenSource.
+	TokenSource TokenSource
+
+	// BaseURL is the base URL of the API, for instance to use a proxy. The
+	// default is DefaultBaseURL.
+	BaseURL string
+
+	// GraphBaseURL is the base URL used by Graph. The default is
+	// DefaultGraphURL.
+	GraphBaseURL string
+
 	// HTTPClient sets a custom HTTP Client used to make requests.
 	HTTPClient *http.Client
 
-	// KeepRawBody set to true will store the raw API response body
-	// of the last request in RawBody.
+	// Retry configures retries of failed requests. Nil disables retries.
+	Retry *RetryPolicy
+
+	// RateLimiter, if set, paces requests to stay within the quota
+	// reported by the API.
+	RateLimiter RateLimiter
+
+	// Interceptors wrap the execution of every request, in order. The
+	// first interceptor is the outermost.
+	Interceptors []Interceptor
+
+	// Cache, if set, caches the responses of GET requests.
+	Cache *Cache
+
+	// Logger, if set, receives debug events for requests, pagination and
+	// retries. Secrets are redacted.
+	Logger *slog.Logger
+
+	// KeepRawBody set to true will store the raw API response body of each
+	// call in its Response.RawBody.
 	KeepRawBody bool
-	RawBody     io.Reader
 
-	// Header contains the raw API response header of the last request.
-	Header http.Header
+	quota quota
+}
+
+// RateLimit returns the quota reported by the most recent response, less any
+// requests made since.
+func (api *API) RateLimit() RateLimit {
+	return api.quota.get()
 }
 
 // New creates an API with either a ClientID OR an accessToken. Only one is
 // required. Access tokens are preferred because they keep rate limiting down.
-// If enforceSignedRequest is set to true, then clientSecret is required
+// If enforceSignedRequest is set to true, then clientSecret is required.
+//
+// New panics if the arguments are invalid. Use NewClient to handle the error
+// and for more options.
 func New(clientID string, clientSecret string, accessToken string, enforceSignedRequest bool) *API {
-	if clientID == "" && accessToken == "" {
-		panic("ClientID or AccessToken must be given to create an API")
+	opts := []Option{
+		WithClientID(clientID),
+		WithClientSecret(clientSecret),
+		WithAccessToken(accessToken),
 	}
-
-	if enforceSignedRequest && clientSecret == "" {
-		panic("ClientSecret is required for signed request")
+	if enforceSignedRequest {
+		opts = append(opts, WithSignedRequests())
 	}
-
-	return &API{
-		ClientID:             clientID,
-		ClientSecret:         clientSecret,
-		AccessToken:          accessToken,
-		HTTPClient:           &http.Client{},
-		EnforceSignedRequest: enforceSignedRequest,
+	api, err := NewClient(opts...)
+	if err != nil {
+		panic(err)
 	}
+	return api
 }
 
 // -- Implementation of request --
@@ -99,95 +141,218 @@ func buildGetRequest(urlStr string, params url.Values) (*http.Request, error) {
 	return http.NewRequest("GET", u.String(), nil)
 }
 
-func (api *API) extendParams(p url.Values) url.Values {
+// buildRequest builds a request for method. POST params are sent as a form
+// body. DELETE params are sent in the query, as the API expects.
+func buildRequest(method string, urlStr string, params url.Values) (*http.Request, error) {
+	switch method {
+	case "GET", "DELETE":
+		req, err := buildGetRequest(urlStr, params)
+		if err != nil {
+			return nil, err
+		}
+		req.Method = method
+		return req, nil
+	}
+	req, err := http.NewRequest(method, urlStr, strings.NewReader(params.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req, nil
+}
+
+func (api *API) extendParams(p url.Values, accessToken string) url.Values {
 	if p == nil {
 		p = url.Values{}
 	}
-	if api.AccessToken != "" {
-		p.Set("access_token", api.AccessToken)
+	if accessToken != "" {
+		p.Set("access_token", accessToken)
 	} else {
 		p.Set("client_id", api.ClientID)
 	}
 	return p
 }
 
-func (api *API) get(ctx context.Context, path string, params url.Values, r interface{}) error {
-	params = api.extendParams(params)
-	// Sign request if ForceSignedRequest is set to true
-	if api.EnforceSignedRequest {
-		params = signParams(path, params, api.ClientSecret)
+func (api *API) get(ctx context.Context, endpoint string, path string, params url.Values, r interface{}) error {
+	return api.request(ctx, "GET", endpoint, path, params, r)
+}
+
+func (api *API) post(ctx context.Context, endpoint string, path string, params url.Values, r interface{}) error {
+	return api.request(ctx, "POST", endpoint, path, params, r)
+}
+
+func (api *API) delete(ctx context.Context, endpoint string, path string, params url.Values, r interface{}) error {
+	return api.request(ctx, "DELETE", endpoint, path, params, r)
+}
+
+func (api *API) request(ctx context.Context, method string, endpoint string, path string, params url.Values, r interface{}) error {
+	return api.execute(ctx, &Request{
+		Endpoint: endpoint,
+		Method:   method,
+		Path:     path,
+		Params:   ensureParams(params),
+		Result:   r,
+	})
+}
+
+// send is the Handler at the end of the interceptor chain. It adds
+// credentials, signs, and makes the HTTP request.
+func (api *API) send(ctx context.Context, req *Request) error {
+	if req.graph {
+		return api.sendGraph(ctx, req)
 	}
 
-	req, err := buildGetRequest(urlify(path), params)
+	token, err := api.accessToken(ctx)
 	if err != nil {
 		return err
 	}
-	return api.do(ctx, req, r)
-}
+	params := api.extendParams(copyParams(req.Params), token)
+	// Sign request if ForceSignedRequest is set to true. The signature
+	// covers all params, whether sent in the query or the body.
+	if api.EnforceSignedRequest {
+		params = signParams(req.Path, params, api.ClientSecret)
+	}
 
-func (api *API) do(ctx context.Context, req *http.Request, r interface{}) error {
-	req = req.WithContext(ctx)
-	resp, err := api.HTTPClient.Do(req)
+	httpReq, err := buildRequest(req.Method, api.urlify(req.Path), params)
 	if err != nil {
 		return err
 	}
-	defer func() {
-		io.CopyN(ioutil.Discard, resp.Body, 512)
-		resp.Body.Close()
-	}()
-
-	api.Header = resp.Header
+	if err := api.sendHTTP(ctx, req, httpReq); err != nil {
+		return err
+	}
+	setPageBaseURL(req.Result, api.baseURL())
+	return nil
+}
 
-	if resp.StatusCode != 200 {
-		return api.apiError(resp)
+// sendHTTP sends the HTTP request for req, via the Cache if there is one.
+func (api *API) sendHTTP(ctx context.Context, req *Request, httpReq *http.Request) error {
+	if api.Cache != nil {
+		return api.sendCached(ctx, req, httpReq)
 	}
+	_, _, err := api.do(ctx, httpReq, req.Result)
+	return unexpectedNotModified(err)
+}
 
-	return api.decodeResponse(resp.Body, r)
+// do sends req, retrying as configured, and decodes the response into r. It
+// returns the metadata and the raw body of the last response.
+func (api *API) do(ctx context.Context, req *http.Request, r interface{}) (*Response, []byte, error) {
+	for attempt := 1; ; attempt++ {
+		resp, body, err := api.doOnce(ctx, req, r)
+		if err == nil {
+			return resp, body, nil
+		}
+		delay, ok := api.Retry.delay(ctx, req, attempt, err, resp)
+		if !ok {
+			return resp, body, err
+		}
+		api.logger().InfoContext(ctx, "instagram: retrying request",
+			"method", req.Method,
+			"url", redactURL(req.URL),
+			"attempt", attempt,
+			"delay", delay,
+			"error", err)
+		if api.Retry.OnRetry != nil {
+			api.Retry.OnRetry(RetryEvent{
+				Attempt: attempt,
+				Delay:   delay,
+				Err:     err,
+				Request: req,
+			})
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return resp, body, err
+		}
+	}
 }
 
-func (api *API) decodeResponse(body io.Reader, to interface{}) error {
+// doOnce makes a single attempt at req. The returned Response is nil if no
+// response was received.
+func (api *API) doOnce(ctx context.Context, req *http.Request, r interface{}) (*Response, []byte, error) {
+	if api.RateLimiter != nil {
+		if err := api.RateLimiter.Wait(ctx, api.quota.get()); err != nil {
+			return nil, nil, err
+		}
+	}
+	api.quota.take()
+
+	log := api.logger().With("method", req.Method, "url", redactURL(req.URL))
+	start := time.Now()
+	resp, err := api.HTTPClient.Do(req.Clone(ctx))
+	if err != nil {
+		log.DebugContext(ctx, "instagram: request failed", "error", err)
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	log.DebugContext(ctx, "instagram: request",
+		"status", resp.StatusCode,
+		"duration", time.Since(start))
+
+	meta := newResponse(resp)
+	api.quota.update(meta.RateLimit)
+	if rr, ok := r.(responder); ok {
+		rr.setResponse(meta)
+	}
 
-	var r io.Reader
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return meta, nil, err
+	}
 	if api.KeepRawBody {
-		var buf bytes.Buffer
-		r = io.TeeReader(body, &buf)
-		api.RawBody = &buf
-	} else {
-		r = body
+		meta.RawBody = body
 	}
 
-	err := json.NewDecoder(r).Decode(to)
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return meta, body, decodeResponse(bytes.NewReader(body), r)
+	case http.StatusNotModified:
+		return meta, body, errNotModified
+	default:
+		return meta, body, apiError(resp, bytes.NewReader(body))
+	}
+}
 
-	if err != nil {
-		return fmt.Errorf("instagram: error decoding body; %s", err.Error())
+func decodeResponse(body io.Reader, to interface{}) error {
+	if err := json.NewDecoder(body).Decode(to); err != nil {
+		return &DecodeError{Err: err}
 	}
 	return nil
 }
 
-func (api *API) apiError(resp *http.Response) error {
-	m := new(metaResponse)
-	if err := api.decodeResponse(resp.Body, m); err != nil {
-		return err
+// apiError decodes the error in an unsuccessful response, either the
+// metadata of the API or the error object of the Graph API.
+func apiError(resp *http.Response, body io.Reader) error {
+	var m struct {
+		Meta  *Meta
+		Error *GraphError
 	}
-
-	var err MetaError
-	if m.Meta != nil {
-		err = MetaError(*m.Meta)
-	} else {
-		err = MetaError(Meta{Code: resp.StatusCode, ErrorMessage: resp.Status})
+	if err := decodeResponse(body, &m); err != nil || (m.Meta == nil && m.Error == nil) {
+		// Proxies and load balancers may respond with HTML.
+		return &MetaError{Code: resp.StatusCode, ErrorMessage: resp.Status}
+	}
+	if m.Error != nil {
+		m.Error.StatusCode = resp.StatusCode
+		return m.Error
 	}
+	err := MetaError(*m.Meta)
 	return &err
 }
 
-func urlify(path string) string {
-	return baseURL + path
+func (api *API) baseURL() string {
+	if api.BaseURL == "" {
+		return DefaultBaseURL
+	}
+	return strings.TrimSuffix(api.BaseURL, "/")
 }
 
-// MetaError is an error from response metadata.
-type MetaError Meta
+func (api *API) urlify(path string) string {
+	return api.baseURL() + path
+}
 
-func (m *MetaError) Error() string {
-	return fmt.Sprintf("Error making api call: Code %d %s %s", m.Code, m.ErrorType, m.ErrorMessage)
+func (api *API) graphBaseURL() string {
+	if api.GraphBaseURL == "" {
+		return DefaultGraphURL
+	}
+	return strings.TrimSuffix(api.GraphBaseURL, "/")
 }
 
 func ensureParams(v url.Values) url.Values {
@@ -196,3 +361,11 @@ func ensureParams(v url.Values) url.Values {
 	}
 	return v
 }
+
+func copyParams(v url.Values) url.Values {
+	c := make(url.Values, len(v))
+	for k, vv := range v {
+		c[k] = append([]string(nil), vv...)
+	}
+	return c
+}
@@ -0,0 +1,81 @@
+package instagram
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+func TestResponseMetadata(t *testing.T) {
+	api, server := newTestAPIServer(t, func(r *http.Request) string {
+		return "comments"
+	})
+	defer server.Close()
+	api.KeepRawBody = true
+
+	ctx := context.Background()
+	resp, err := api.GetMediaRecentComments(ctx, "123", nil)
+	if err != nil {
+		t.Fatalf("GetMediaRecentComments: %s", err)
+	}
+
+	r := resp.Response
+	if r == nil {
+		t.Fatalf("Response is nil")
+	}
+	if got, want := r.StatusCode, 200; got != want {
+		t.Errorf("StatusCode got %d want %d", got, want)
+	}
+	if got, want := r.Header.Get("Content-Type"), "application/json"; got != want {
+		t.Errorf("Content-Type got %q want %q", got, want)
+	}
+	if got, want := r.RateLimit.Remaining, 4999; got != want {
+		t.Errorf("RateLimit.Remaining got %d want %d", got, want)
+	}
+	if !bytes.Contains(r.RawBody, []byte(`"18034730665003447"`)) {
+		t.Errorf("RawBody does not contain the response: %s", r.RawBody)
+	}
+}
+
+func TestConcurrentCalls(t *testing.T) {
+	api, server := newTestAPIServer(t, func(r *http.Request) string {
+		if r.URL.Path == "/v1/users/self/media/recent" {
+			return "video"
+		}
+		return "comments"
+	})
+	defer server.Close()
+	api.KeepRawBody = true
+
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			resp, err := api.GetRecentMedia(ctx, nil)
+			if err != nil {
+				t.Errorf("GetRecentMedia: %s", err)
+				return
+			}
+			if !bytes.Contains(resp.Response.RawBody, []byte(`"video"`)) {
+				t.Errorf("GetRecentMedia got another call's body")
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			resp, err := api.GetMediaRecentComments(ctx, "123", nil)
+			if err != nil {
+				t.Errorf("GetMediaRecentComments: %s", err)
+				return
+			}
+			if bytes.Contains(resp.Response.RawBody, []byte(`"video"`)) {
+				t.Errorf("GetMediaRecentComments got another call's body")
+			}
+		}()
+	}
+	wg.Wait()
+}
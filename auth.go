@@ -4,10 +4,21 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
-	"log"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
+)
+
+const (
+	// DefaultAuthorizeURL is the URL users are sent to to authorize an
+	// app.
+	DefaultAuthorizeURL = "https://api.instagram.com/oauth/authorize/"
+
+	// DefaultTokenURL is the URL an authorization code is traded at for
+	// an access token.
+	DefaultTokenURL = "https://api.instagram.com/oauth/access_token"
 )
 
 // NewOAuth initializes a new OAuth flow.
@@ -26,11 +37,55 @@ type OAuth struct {
 	ClientSecret string
 	RedirectURI  string
 	HTTPClient   *http.Client
+
+	// AuthorizeURL is the URL users are sent to. The default is
+	// DefaultAuthorizeURL.
+	AuthorizeURL string
+
+	// TokenURL is the URL the code is traded at. The default is
+	// DefaultTokenURL.
+	TokenURL string
+
+	// GraphURL is the base URL long-lived tokens are exchanged and
+	// refreshed at. The default is DefaultGraphURL.
+	GraphURL string
+
+	// Logger, if set, receives debug events for the flow. Secrets are
+	// redacted.
+	Logger *slog.Logger
+}
+
+func (o OAuth) authorizeURL() string {
+	if o.AuthorizeURL == "" {
+		return DefaultAuthorizeURL
+	}
+	return o.AuthorizeURL
 }
 
-// GetAuthorizeURL returns a URL to send a user to.
+func (o OAuth) tokenURL() string {
+	if o.TokenURL == "" {
+		return DefaultTokenURL
+	}
+	return o.TokenURL
+}
+
+func (o OAuth) graphURL() string {
+	if o.GraphURL == "" {
+		return DefaultGraphURL
+	}
+	return strings.TrimSuffix(o.GraphURL, "/")
+}
+
+// GetAuthorizeURL returns a URL to send a user to. If AuthorizeURL is
+// malformed, DefaultAuthorizeURL is used.
 func (o OAuth) GetAuthorizeURL(state string) string {
-	authURL, _ := url.Parse("https://api.instagram.com/oauth/authorize/")
+	authURL, err := url.Parse(o.authorizeURL())
+	if err != nil {
+		o.logger().Warn("instagram: invalid authorize url, using the default",
+			"url", o.AuthorizeURL,
+			"error", err)
+		authURL, _ = url.Parse(DefaultAuthorizeURL)
+	}
 	query := authURL.Query()
 	query.Set("client_id", o.ClientID)
 	query.Set("redirect_uri", o.RedirectURI)
@@ -38,10 +93,9 @@ func (o OAuth) GetAuthorizeURL(state string) string {
 	if state != "" {
 		query.Set("state", state)
 	}
-	log.Printf("E %s", query.Encode())
 	authURL.RawQuery = query.Encode()
 
-	log.Printf("U %s", authURL.String())
+	o.logger().Debug("instagram: authorize url", "url", redactURL(authURL))
 	return authURL.String()
 }
 
@@ -70,17 +124,22 @@ func (o OAuth) GetAccessToken(ctx context.Context, code string) (string, error)
 	form.Set("grant_type", "authorization_code")
 	form.Set("code", code)
 
-	req, err := http.NewRequest("POST", "https://api.instagram.com/oauth/access_token", strings.NewReader(form.Encode()))
+	req, err := http.NewRequest("POST", o.tokenURL(), strings.NewReader(form.Encode()))
 	if err != nil {
 		return "", err
 	}
 	req = req.WithContext(ctx)
 	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
 
+	log := o.logger().With("url", o.tokenURL())
+	log.DebugContext(ctx, "instagram: exchanging code for access token")
 	resp, err := o.HTTPClient.Do(req)
 	if err != nil {
+		log.DebugContext(ctx, "instagram: token exchange failed", "error", err)
 		return "", err
 	}
+	defer resp.Body.Close()
+	log.DebugContext(ctx, "instagram: token exchange", "status", resp.StatusCode)
 
 	type response struct {
 		AccessToken string `json:"access_token"`
@@ -92,3 +151,63 @@ func (o OAuth) GetAccessToken(ctx context.Context, code string) (string, error)
 	}
 	return tokenResponse.AccessToken, nil
 }
+
+// ExchangeForLongLivedToken trades a short-lived access token, as returned by
+// GetAccessToken, for a long-lived token that is valid for 60 days.
+// Graph API: GET /access_token
+func (o OAuth) ExchangeForLongLivedToken(ctx context.Context, accessToken string) (*Token, error) {
+	params := url.Values{}
+	params.Set("grant_type", "ig_exchange_token")
+	params.Set("client_secret", o.ClientSecret)
+	params.Set("access_token", accessToken)
+	return o.graphToken(ctx, "/access_token", params)
+}
+
+// RefreshToken refreshes a long-lived access token that is at least 24 hours
+// old and not expired. The refreshed token is valid for 60 days.
+// Graph API: GET /refresh_access_token
+func (o OAuth) RefreshToken(ctx context.Context, accessToken string) (*Token, error) {
+	params := url.Values{}
+	params.Set("grant_type", "ig_refresh_token")
+	params.Set("access_token", accessToken)
+	return o.graphToken(ctx, "/refresh_access_token", params)
+}
+
+func (o OAuth) graphToken(ctx context.Context, path string, params url.Values) (*Token, error) {
+	req, err := buildGetRequest(o.graphURL()+path, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+
+	log := o.logger().With("url", redactURL(req.URL))
+	log.DebugContext(ctx, "instagram: requesting long-lived token")
+	resp, err := o.HTTPClient.Do(req)
+	if err != nil {
+		log.DebugContext(ctx, "instagram: token request failed", "error", err)
+		return nil, err
+	}
+	defer resp.Body.Close()
+	log.DebugContext(ctx, "instagram: token request", "status", resp.StatusCode)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, apiError(resp, resp.Body)
+	}
+
+	var tokenResponse struct {
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := decodeResponse(resp.Body, &tokenResponse); err != nil {
+		return nil, err
+	}
+	token := &Token{
+		AccessToken: tokenResponse.AccessToken,
+		TokenType:   tokenResponse.TokenType,
+	}
+	if tokenResponse.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(tokenResponse.ExpiresIn) * time.Second)
+	}
+	return token, nil
+}
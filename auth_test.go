@@ -1,7 +1,10 @@
 package instagram
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"net/http"
 	"net/url"
 	"testing"
 )
@@ -22,6 +25,60 @@ func TestGetAuthorizeURL(t *testing.T) {
 	}
 }
 
+func TestGetAuthorizeURLCustom(t *testing.T) {
+	oauth := OAuth{
+		ClientID:     "c1",
+		RedirectURI:  "http://localhost/",
+		AuthorizeURL: "http://staging.example.com/oauth/authorize/",
+	}
+
+	want := "http://staging.example.com/oauth/authorize/?client_id=c1&redirect_uri=http%3A%2F%2Flocalhost%2F&response_type=code"
+
+	if got := oauth.GetAuthorizeURL(""); got != want {
+		t.Errorf("Authroize URL got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestGetAuthorizeURLInvalid(t *testing.T) {
+	oauth := OAuth{
+		ClientID:     "c1",
+		RedirectURI:  "http://localhost/",
+		AuthorizeURL: "://bad",
+	}
+
+	want := "https://api.instagram.com/oauth/authorize/?client_id=c1&redirect_uri=http%3A%2F%2Flocalhost%2F&response_type=code"
+
+	if got := oauth.GetAuthorizeURL(""); got != want {
+		t.Errorf("Authorize URL got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestGetAccessToken(t *testing.T) {
+	mux, server := initTestServer()
+	defer server.Close()
+
+	mux.HandleFunc("/oauth/access_token", func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.PostFormValue("code"), "c0de"; got != want {
+			t.Errorf("Code got %q want %q", got, want)
+		}
+		if got, want := r.PostFormValue("client_secret"), "s1"; got != want {
+			t.Errorf("Secret got %q want %q", got, want)
+		}
+		fmt.Fprint(w, `{"access_token":"t0ken","user":{"id":"1"}}`)
+	})
+
+	oauth := NewOAuth("c1", "s1", "http://localhost/")
+	oauth.TokenURL = server.URL + "/oauth/access_token"
+
+	token, err := oauth.GetAccessToken(context.Background(), "c0de")
+	if err != nil {
+		t.Fatalf("GetAccessToken: %s", err)
+	}
+	if got, want := token, "t0ken"; got != want {
+		t.Errorf("Token got %q want %q", got, want)
+	}
+}
+
 func TestGetCodeFromRedirect(t *testing.T) {
 	tests := []struct {
 		desc     string
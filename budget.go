@@ -0,0 +1,183 @@
+package instagram
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultHashtagLimit is the number of unique hashtags an account may
+	// search in DefaultHashtagWindow.
+	DefaultHashtagLimit = 30
+
+	// DefaultHashtagWindow is the rolling window of DefaultHashtagLimit.
+	DefaultHashtagWindow = 7 * 24 * time.Hour
+)
+
+// HashtagStore stores the hashtags searched by each account, for a
+// HashtagBudget. Implementations must be safe for concurrent use.
+type HashtagStore interface {
+	// Searches returns the hashtags searched by userID since the given
+	// time, with the time each was first searched.
+	Searches(userID string, since time.Time) (map[string]time.Time, error)
+
+	// Record records that userID searched hashtag at t.
+	Record(userID string, hashtag string, t time.Time) error
+}
+
+// HashtagBudget tracks the unique hashtags searched by each account, and
+// refuses searches over the limit of the Graph API before they are made.
+// A hashtag counts against the budget from its first search until the window
+// has passed; searching it again in the meantime is free.
+type HashtagBudget struct {
+	// Store keeps the searches. The default is a MemoryHashtagStore.
+	Store HashtagStore
+
+	// Limit is the number of unique hashtags per Window. The default is
+	// DefaultHashtagLimit.
+	Limit int
+
+	// Window is the rolling window. The default is DefaultHashtagWindow.
+	Window time.Duration
+
+	// mu makes checking and recording a search atomic.
+	mu sync.Mutex
+}
+
+// NewHashtagBudget returns a HashtagBudget with the default limit, kept in
+// store.
+func NewHashtagBudget(store HashtagStore) *HashtagBudget {
+	return &HashtagBudget{Store: store}
+}
+
+// Remaining returns how many more unique hashtags userID can search now.
+func (b *HashtagBudget) Remaining(userID string) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	searches, err := b.store().Searches(userID, time.Now().Add(-b.window()))
+	if err != nil {
+		return 0, err
+	}
+	if n := b.limit() - len(searches); n > 0 {
+		return n, nil
+	}
+	return 0, nil
+}
+
+// spend records a search of hashtag by userID, or returns a
+// *HashtagBudgetError if it is over the budget. Searches are recorded before
+// they are made, so that failed searches count, as they may for the Graph
+// API.
+func (b *HashtagBudget) spend(userID string, hashtag string, now time.Time) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	searches, err := b.store().Searches(userID, now.Add(-b.window()))
+	if err != nil {
+		return err
+	}
+	if _, ok := searches[hashtag]; ok {
+		return nil
+	}
+	if len(searches) >= b.limit() {
+		var oldest time.Time
+		for _, t := range searches {
+			if oldest.IsZero() || t.Before(oldest) {
+				oldest = t
+			}
+		}
+		return &HashtagBudgetError{
+			UserID:  userID,
+			Hashtag: hashtag,
+			Limit:   b.limit(),
+			ResetAt: oldest.Add(b.window()),
+		}
+	}
+	return b.store().Record(userID, hashtag, now)
+}
+
+// store returns b.Store, setting it to a MemoryHashtagStore if it is nil. It
+// must be called with mu held.
+func (b *HashtagBudget) store() HashtagStore {
+	if b.Store == nil {
+		b.Store = NewMemoryHashtagStore()
+	}
+	return b.Store
+}
+
+func (b *HashtagBudget) limit() int {
+	if b.Limit == 0 {
+		return DefaultHashtagLimit
+	}
+	return b.Limit
+}
+
+func (b *HashtagBudget) window() time.Duration {
+	if b.Window == 0 {
+		return DefaultHashtagWindow
+	}
+	return b.Window
+}
+
+// HashtagBudgetError is returned when searching a hashtag would exceed the
+// HashtagBudget. It matches ErrRateLimited with errors.Is.
+type HashtagBudgetError struct {
+	UserID  string
+	Hashtag string
+	Limit   int
+
+	// ResetAt is when the budget has room for another hashtag.
+	ResetAt time.Time
+}
+
+func (e *HashtagBudgetError) Error() string {
+	return fmt.Sprintf("instagram: searching #%s would exceed %d hashtags per week for user %s, until %s",
+		e.Hashtag, e.Limit, e.UserID, e.ResetAt.Format(time.RFC3339))
+}
+
+// Is returns true for ErrRateLimited.
+func (e *HashtagBudgetError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+// MemoryHashtagStore is an in-memory HashtagStore. Searches are lost when
+// the process exits; persist them to share a budget between processes.
+type MemoryHashtagStore struct {
+	mu       sync.Mutex
+	searches map[string]map[string]time.Time
+}
+
+// NewMemoryHashtagStore returns an empty MemoryHashtagStore.
+func NewMemoryHashtagStore() *MemoryHashtagStore {
+	return &MemoryHashtagStore{searches: make(map[string]map[string]time.Time)}
+}
+
+// Searches implements HashtagStore.
+func (s *MemoryHashtagStore) Searches(userID string, since time.Time) (map[string]time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := make(map[string]time.Time)
+	for tag, t := range s.searches[userID] {
+		if t.After(since) {
+			res[tag] = t
+		} else {
+			delete(s.searches[userID], tag)
+		}
+	}
+	return res, nil
+}
+
+// Record implements HashtagStore.
+func (s *MemoryHashtagStore) Record(userID string, hashtag string, t time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.searches[userID] == nil {
+		s.searches[userID] = make(map[string]time.Time)
+	}
+	s.searches[userID][hashtag] = t
+	return nil
+}
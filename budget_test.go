@@ -0,0 +1,94 @@
+package instagram
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestHashtagBudget(t *testing.T) {
+	start := time.Date(2019, 2, 14, 0, 0, 0, 0, time.UTC)
+	b := NewHashtagBudget(NewMemoryHashtagStore())
+	b.Limit = 2
+
+	steps := []struct {
+		desc    string
+		user    string
+		tag     string
+		at      time.Duration
+		wantErr bool
+	}{
+		{desc: "first", user: "1", tag: "coke", at: 0},
+		{desc: "second", user: "1", tag: "pepsi", at: time.Hour},
+		{desc: "repeat is free", user: "1", tag: "coke", at: 2 * time.Hour},
+		{desc: "over budget", user: "1", tag: "fanta", at: 3 * time.Hour, wantErr: true},
+		{desc: "other user", user: "2", tag: "fanta", at: 3 * time.Hour},
+		{desc: "still over budget", user: "1", tag: "fanta", at: 7*24*time.Hour - time.Minute, wantErr: true},
+		{desc: "window passed", user: "1", tag: "fanta", at: 7*24*time.Hour + time.Minute},
+		{desc: "over budget again", user: "1", tag: "sprite", at: 7*24*time.Hour + time.Minute, wantErr: true},
+	}
+	for _, s := range steps {
+		err := b.spend(s.user, s.tag, start.Add(s.at))
+		if !s.wantErr {
+			if err != nil {
+				t.Errorf("%s: spend: %s", s.desc, err)
+			}
+			continue
+		}
+		var berr *HashtagBudgetError
+		if !errors.As(err, &berr) {
+			t.Fatalf("%s: Error got %v want *HashtagBudgetError", s.desc, err)
+		}
+		if !errors.Is(err, ErrRateLimited) {
+			t.Errorf("%s: Error got %v want %v", s.desc, err, ErrRateLimited)
+		}
+		if berr.Hashtag != s.tag || berr.UserID != s.user || berr.Limit != 2 {
+			t.Errorf("%s: Error got %#v", s.desc, berr)
+		}
+	}
+
+	var berr *HashtagBudgetError
+	if err := b.spend("1", "tab", start.Add(7*24*time.Hour+30*time.Minute)); !errors.As(err, &berr) {
+		t.Fatalf("Error got %v want *HashtagBudgetError", err)
+	}
+	if got, want := berr.ResetAt, start.Add(7*24*time.Hour+time.Hour); !got.Equal(want) {
+		t.Errorf("ResetAt got %s want %s", got, want)
+	}
+}
+
+func TestHashtagBudgetRemaining(t *testing.T) {
+	b := NewHashtagBudget(NewMemoryHashtagStore())
+	now := time.Now()
+	for _, tag := range []string{"coke", "pepsi", "coke"} {
+		if err := b.spend("1", tag, now); err != nil {
+			t.Fatalf("spend: %s", err)
+		}
+	}
+	if err := b.spend("1", "fanta", now.Add(-8*24*time.Hour)); err != nil {
+		t.Fatalf("spend: %s", err)
+	}
+	n, err := b.Remaining("1")
+	if err != nil {
+		t.Fatalf("Remaining: %s", err)
+	}
+	if got, want := n, DefaultHashtagLimit-2; got != want {
+		t.Errorf("Remaining got %d want %d", got, want)
+	}
+}
+
+func TestHashtagBudgetDefaultStore(t *testing.T) {
+	b := &HashtagBudget{Limit: 1}
+	n, err := b.Remaining("1")
+	if err != nil {
+		t.Fatalf("Remaining: %s", err)
+	}
+	if got, want := n, 1; got != want {
+		t.Errorf("Remaining got %d want %d", got, want)
+	}
+	if err := b.spend("1", "coke", time.Now()); err != nil {
+		t.Fatalf("spend: %s", err)
+	}
+	if err := b.spend("1", "pepsi", time.Now()); err == nil {
+		t.Errorf("Want error over the limit")
+	}
+}
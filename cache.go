@@ -0,0 +1,264 @@
+package instagram
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// errNotModified is returned by API.do for a 304 response.
+var errNotModified = errors.New("instagram: not modified")
+
+// unexpectedNotModified replaces errNotModified when the request wasn't
+// conditional, so there is nothing to revalidate.
+func unexpectedNotModified(err error) error {
+	if err == errNotModified {
+		return &MetaError{Code: http.StatusNotModified, ErrorMessage: "unexpected 304 Not Modified"}
+	}
+	return err
+}
+
+// Cache configures caching of GET responses. Responses are keyed by path and
+// params, without the access token or signature, so a Cache must not be
+// shared between APIs with different credentials.
+type Cache struct {
+	// Store holds the cached responses.
+	Store CacheStore
+
+	// TTL is how long a response stays fresh, by Request.Endpoint, for
+	// instance "GetSelf".
+	TTL map[string]time.Duration
+
+	// DefaultTTL is used for endpoints not in TTL. Zero means those
+	// endpoints are not cached.
+	DefaultTTL time.Duration
+}
+
+// CacheStatus describes how a response was served with respect to the Cache.
+type CacheStatus string
+
+// Values of Response.Cache. The zero value means the request was not
+// cacheable.
+const (
+	// CacheHit means the response was served from the cache.
+	CacheHit CacheStatus = "hit"
+
+	// CacheMiss means the response was fetched and stored.
+	CacheMiss CacheStatus = "miss"
+
+	// CacheRevalidated means a stale response was confirmed unchanged by
+	// the server using its ETag.
+	CacheRevalidated CacheStatus = "revalidated"
+)
+
+// CacheStore stores cached responses. Implementations must be safe for
+// concurrent use. Caching is best effort, so stores drop entries they fail
+// to save.
+type CacheStore interface {
+	Get(key string) (*CacheEntry, bool)
+	Set(key string, e *CacheEntry)
+}
+
+// CacheEntry is a cached response. Entries are not modified once stored.
+type CacheEntry struct {
+	Header  http.Header `json:"header"`
+	Body    []byte      `json:"body"`
+	ETag    string      `json:"etag,omitempty"`
+	Expires time.Time   `json:"expires"`
+}
+
+func (c *Cache) ttl(endpoint string) time.Duration {
+	if ttl, ok := c.TTL[endpoint]; ok {
+		return ttl
+	}
+	return c.DefaultTTL
+}
+
+func cacheKey(req *Request) string {
+	params := copyParams(req.Params)
+	params.Del("sig")
+	params.Del("access_token")
+	params.Del("client_id")
+	key := req.Path + "?" + params.Encode()
+	if req.graph {
+		key = "graph:" + key
+	}
+	return key
+}
+
+// sendCached sends a request via the Cache.
+func (api *API) sendCached(ctx context.Context, req *Request, httpReq *http.Request) error {
+	c := api.Cache
+	ttl := c.ttl(req.Endpoint)
+	if req.Method != "GET" || req.noCache || ttl <= 0 || c.Store == nil {
+		_, _, err := api.do(ctx, httpReq, req.Result)
+		return unexpectedNotModified(err)
+	}
+
+	key := cacheKey(req)
+	entry, ok := c.Store.Get(key)
+	if ok && time.Now().Before(entry.Expires) {
+		api.logger().DebugContext(ctx, "instagram: cache hit",
+			"endpoint", req.Endpoint,
+			"key", key)
+		meta := &Response{
+			StatusCode: http.StatusOK,
+			Header:     entry.Header,
+			Cache:      CacheHit,
+		}
+		return api.decodeCached(entry, meta, req.Result)
+	}
+	revalidate := ok && entry.ETag != ""
+	if revalidate {
+		httpReq.Header.Set("If-None-Match", entry.ETag)
+	}
+
+	meta, body, err := api.do(ctx, httpReq, req.Result)
+	switch {
+	case err == errNotModified && revalidate:
+		fresh := *entry
+		fresh.Expires = time.Now().Add(ttl)
+		c.Store.Set(key, &fresh)
+		meta.Cache = CacheRevalidated
+		return api.decodeCached(entry, meta, req.Result)
+	case err != nil:
+		return unexpectedNotModified(err)
+	}
+
+	meta.Cache = CacheMiss
+	c.Store.Set(key, &CacheEntry{
+		Header:  meta.Header,
+		Body:    body,
+		ETag:    meta.Header.Get("ETag"),
+		Expires: time.Now().Add(ttl),
+	})
+	return nil
+}
+
+func (api *API) decodeCached(entry *CacheEntry, meta *Response, r interface{}) error {
+	if api.KeepRawBody {
+		meta.RawBody = entry.Body
+	}
+	if rr, ok := r.(responder); ok {
+		rr.setResponse(meta)
+	}
+	return decodeResponse(bytes.NewReader(entry.Body), r)
+}
+
+// MemoryCache is an in-memory CacheStore that evicts the least recently used
+// entries.
+type MemoryCache struct {
+	size int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+}
+
+type memoryCacheItem struct {
+	key   string
+	entry *CacheEntry
+}
+
+// NewMemoryCache returns a MemoryCache that holds up to size entries.
+func NewMemoryCache(size int) *MemoryCache {
+	return &MemoryCache{
+		size:    size,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+// Get implements CacheStore.
+func (c *MemoryCache) Get(key string) (*CacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.lru.MoveToFront(el)
+	return el.Value.(*memoryCacheItem).entry, true
+}
+
+// Set implements CacheStore.
+func (c *MemoryCache) Set(key string, e *CacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		el.Value.(*memoryCacheItem).entry = e
+		c.lru.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(&memoryCacheItem{key, e})
+	for c.lru.Len() > c.size {
+		el := c.lru.Back()
+		c.lru.Remove(el)
+		delete(c.entries, el.Value.(*memoryCacheItem).key)
+	}
+}
+
+// DiskCache is a CacheStore that keeps each entry in a file in a directory.
+type DiskCache struct {
+	dir string
+}
+
+// NewDiskCache returns a DiskCache in dir, creating it if needed.
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &DiskCache{dir: dir}, nil
+}
+
+func (c *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// Get implements CacheStore.
+func (c *DiskCache) Get(key string) (*CacheEntry, bool) {
+	data, err := ioutil.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+	e := new(CacheEntry)
+	if err := json.Unmarshal(data, e); err != nil {
+		return nil, false
+	}
+	return e, true
+}
+
+// Set implements CacheStore.
+func (c *DiskCache) Set(key string, e *CacheEntry) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	// Write and rename so that readers never see a partial entry.
+	tmp, err := ioutil.TempFile(c.dir, "tmp-")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(data)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+	if err := os.Rename(tmp.Name(), c.path(key)); err != nil {
+		os.Remove(tmp.Name())
+	}
+}
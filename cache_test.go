@@ -0,0 +1,195 @@
+package instagram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCache(t *testing.T) {
+	var requests int
+	api, server := newTestAPIServer(t, func(r *http.Request) string {
+		requests++
+		return "image"
+	})
+	defer server.Close()
+
+	api.Cache = &Cache{
+		Store: NewMemoryCache(10),
+		TTL: map[string]time.Duration{
+			"GetRecentMedia": time.Hour,
+		},
+	}
+
+	ctx := context.Background()
+	params := url.Values{"count": {"1"}}
+
+	wantStatus := []CacheStatus{CacheMiss, CacheHit, CacheHit}
+	for i, want := range wantStatus {
+		res, err := api.GetRecentMedia(ctx, params)
+		if err != nil {
+			t.Fatalf("GetRecentMedia: %s", err)
+		}
+		if got := res.Response.Cache; got != want {
+			t.Errorf("Call %d Cache got %q want %q", i, got, want)
+		}
+		if got, want := len(res.Medias), 1; got != want {
+			t.Errorf("Call %d Medias got %d want %d", i, got, want)
+		}
+	}
+	if got, want := requests, 1; got != want {
+		t.Errorf("Requests got %d want %d", got, want)
+	}
+
+	// Different params are cached separately.
+	if _, err := api.GetRecentMedia(ctx, url.Values{"count": {"2"}}); err != nil {
+		t.Fatalf("GetRecentMedia: %s", err)
+	}
+	if got, want := requests, 2; got != want {
+		t.Errorf("Requests got %d want %d", got, want)
+	}
+
+	// Endpoints without a TTL are not cached.
+	for i := 0; i < 2; i++ {
+		res, err := api.GetMediaRecentComments(ctx, "123", nil)
+		if err != nil {
+			t.Fatalf("GetMediaRecentComments: %s", err)
+		}
+		if got, want := res.Response.Cache, CacheStatus(""); got != want {
+			t.Errorf("Cache got %q want %q", got, want)
+		}
+	}
+	if got, want := requests, 4; got != want {
+		t.Errorf("Requests got %d want %d", got, want)
+	}
+}
+
+func TestCacheRevalidate(t *testing.T) {
+	mux, server := initTestServer()
+	defer server.Close()
+
+	var requests int
+	mux.HandleFunc("/v1/users/self", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		fmt.Fprint(w, `{"meta":{"code":200},"data":{"id":"1"}}`)
+	})
+
+	api := &API{
+		AccessToken: "token",
+		BaseURL:     server.URL + "/v1",
+		HTTPClient:  server.Client(),
+		Cache: &Cache{
+			Store:      NewMemoryCache(10),
+			DefaultTTL: time.Nanosecond,
+		},
+	}
+
+	ctx := context.Background()
+	for i, want := range []CacheStatus{CacheMiss, CacheRevalidated, CacheRevalidated} {
+		time.Sleep(time.Millisecond)
+		res, err := api.GetSelf(ctx)
+		if err != nil {
+			t.Fatalf("GetSelf: %s", err)
+		}
+		if got := res.Response.Cache; got != want {
+			t.Errorf("Call %d Cache got %q want %q", i, got, want)
+		}
+		if got, want := res.User.ID, "1"; got != want {
+			t.Errorf("Call %d User ID got %q want %q", i, got, want)
+		}
+	}
+	if got, want := requests, 3; got != want {
+		t.Errorf("Requests got %d want %d", got, want)
+	}
+}
+
+func TestCacheUnexpectedNotModified(t *testing.T) {
+	mux, server := initTestServer()
+	defer server.Close()
+
+	mux.HandleFunc("/v1/users/self", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotModified)
+	})
+
+	for _, cache := range []*Cache{nil, {Store: NewMemoryCache(10), DefaultTTL: time.Minute}} {
+		api := &API{
+			AccessToken: "token",
+			BaseURL:     server.URL + "/v1",
+			HTTPClient:  server.Client(),
+			Cache:       cache,
+		}
+		_, err := api.GetSelf(context.Background())
+		var merr *MetaError
+		if !errors.As(err, &merr) {
+			t.Fatalf("Cache %t: Error got %v want *MetaError", cache != nil, err)
+		}
+		if got, want := merr.Code, http.StatusNotModified; got != want {
+			t.Errorf("Cache %t: Code got %d want %d", cache != nil, got, want)
+		}
+	}
+}
+
+func TestCacheKey(t *testing.T) {
+	a := cacheKey(&Request{Path: "/users/self", Params: url.Values{"count": {"1"}, "sig": {"a"}}})
+	b := cacheKey(&Request{Path: "/users/self", Params: url.Values{"count": {"1"}, "access_token": {"b"}}})
+	if got, want := a, "/users/self?count=1"; got != want {
+		t.Errorf("Key got %q want %q", got, want)
+	}
+	if a != b {
+		t.Errorf("Keys differ by credentials: %q %q", a, b)
+	}
+}
+
+func TestMemoryCacheEviction(t *testing.T) {
+	c := NewMemoryCache(2)
+	c.Set("a", &CacheEntry{ETag: "a"})
+	c.Set("b", &CacheEntry{ETag: "b"})
+	c.Get("a")
+	c.Set("c", &CacheEntry{ETag: "c"})
+
+	if _, ok := c.Get("b"); ok {
+		t.Errorf("Least recently used entry was not evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := c.Get(key); !ok {
+			t.Errorf("Entry %s was evicted", key)
+		}
+	}
+}
+
+func TestDiskCache(t *testing.T) {
+	c, err := NewDiskCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewDiskCache: %s", err)
+	}
+
+	if _, ok := c.Get("/users/self?"); ok {
+		t.Errorf("Got entry from empty cache")
+	}
+
+	want := &CacheEntry{
+		Header:  http.Header{"Etag": {`"v1"`}},
+		Body:    []byte(`{"meta":{"code":200}}`),
+		ETag:    `"v1"`,
+		Expires: time.Date(2019, 2, 14, 19, 43, 40, 0, time.UTC),
+	}
+	c.Set("/users/self?", want)
+
+	got, ok := c.Get("/users/self?")
+	if !ok {
+		t.Fatalf("Entry not found")
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Entry got %#v want %#v", got, want)
+	}
+}
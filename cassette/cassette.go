@@ -0,0 +1,274 @@
+// Package cassette records HTTP interactions to a file and replays them, so
+// that tests can run offline against real API responses.
+//
+// Access tokens, signatures, client secrets and authorization codes are
+// scrubbed from recorded requests and responses. When replaying, requests are
+// matched on method, path and params, ignoring those scrubbed values.
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sync"
+)
+
+// Mode is whether a Transport records or replays.
+type Mode int
+
+const (
+	// Replay serves responses from the cassette file.
+	Replay Mode = iota
+
+	// Record makes real requests and saves them to the cassette file.
+	Record
+)
+
+// Redacted replaces scrubbed values.
+const Redacted = "REDACTED"
+
+// scrubbedParams are the params whose values are scrubbed, and ignored when
+// matching requests.
+var scrubbedParams = []string{"access_token", "sig", "client_secret", "code"}
+
+var (
+	// scrubQuery matches scrubbed params in URLs within a response body,
+	// such as pagination next_url.
+	scrubQuery = regexp.MustCompile(`([?&](?:access_token|sig|client_secret|code)=)[^&"\\\s]+`)
+
+	// scrubJSON matches tokens in a JSON response body, such as the
+	// response to an access token request.
+	scrubJSON = regexp.MustCompile(`("access_token"\s*:\s*)"[^"]*"`)
+)
+
+// Interaction is a recorded request and its response.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a recorded request. Form holds the params of a form body.
+type Request struct {
+	Method string     `json:"method"`
+	URL    string     `json:"url"`
+	Form   url.Values `json:"form,omitempty"`
+}
+
+// Response is a recorded response.
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       string      `json:"body"`
+}
+
+// Transport is an http.RoundTripper that records or replays interactions.
+// Use it as the Transport of API.HTTPClient or OAuth.HTTPClient.
+type Transport struct {
+	// Transport makes the real requests in Record mode. The default is
+	// http.DefaultTransport.
+	Transport http.RoundTripper
+
+	path string
+	mode Mode
+
+	mu           sync.Mutex
+	interactions []*Interaction
+	used         []bool
+}
+
+// New returns a Transport for the cassette file at path. In Replay mode the
+// file is loaded; in Record mode it is written by Save.
+func New(path string, mode Mode) (*Transport, error) {
+	t := &Transport{path: path, mode: mode}
+	if mode == Record {
+		return t, nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &t.interactions); err != nil {
+		return nil, fmt.Errorf("cassette: decoding %s: %s", path, err)
+	}
+	t.used = make([]bool, len(t.interactions))
+	return t, nil
+}
+
+// Client returns an http.Client that uses the Transport.
+func (t *Transport) Client() *http.Client {
+	return &http.Client{Transport: t}
+}
+
+// Save writes the recorded interactions to the cassette file.
+func (t *Transport) Save() error {
+	if t.mode != Record {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	data, err := json.MarshalIndent(t.interactions, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(t.path, append(data, '\n'), 0644)
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req, form, err := readForm(req)
+	if err != nil {
+		return nil, err
+	}
+	if t.mode == Record {
+		return t.record(req, form)
+	}
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	return t.replay(req, form)
+}
+
+func (t *Transport) record(req *http.Request, form url.Values) (*http.Response, error) {
+	rt := t.Transport
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	u := *req.URL
+	u.RawQuery = scrub(u.Query()).Encode()
+
+	var recForm url.Values
+	if form != nil {
+		recForm = scrub(form)
+	}
+
+	scrubbed := scrubQuery.ReplaceAllString(string(body), "${1}"+Redacted)
+	scrubbed = scrubJSON.ReplaceAllString(scrubbed, `${1}"`+Redacted+`"`)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.interactions = append(t.interactions, &Interaction{
+		Request: Request{
+			Method: req.Method,
+			URL:    u.String(),
+			Form:   recForm,
+		},
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header,
+			Body:       scrubbed,
+		},
+	})
+	return resp, nil
+}
+
+func (t *Transport) replay(req *http.Request, form url.Values) (*http.Response, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for i, in := range t.interactions {
+		if t.used[i] || !matches(in.Request, req, form) {
+			continue
+		}
+		t.used[i] = true
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", in.Response.StatusCode, http.StatusText(in.Response.StatusCode)),
+			StatusCode:    in.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        in.Response.Header,
+			Body:          ioutil.NopCloser(bytes.NewBufferString(in.Response.Body)),
+			ContentLength: int64(len(in.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("cassette: no interaction for %s %s", req.Method, req.URL.Path)
+}
+
+func matches(rec Request, req *http.Request, form url.Values) bool {
+	if rec.Method != req.Method {
+		return false
+	}
+	u, err := url.Parse(rec.URL)
+	if err != nil || u.Path != req.URL.Path {
+		return false
+	}
+	return equalParams(u.Query(), req.URL.Query()) && equalParams(rec.Form, form)
+}
+
+func equalParams(a, b url.Values) bool {
+	return strip(a).Encode() == strip(b).Encode()
+}
+
+// readForm returns the params of a form body, without modifying req. The
+// body is read from req.GetBody if possible. Otherwise it is consumed, and
+// the request returned is a copy of req with the body restored.
+func readForm(req *http.Request) (*http.Request, url.Values, error) {
+	if req.Body == nil || req.Header.Get("Content-Type") != "application/x-www-form-urlencoded" {
+		return req, nil, nil
+	}
+	if req.GetBody != nil {
+		rc, err := req.GetBody()
+		if err != nil {
+			return nil, nil, err
+		}
+		defer rc.Close()
+		body, err := ioutil.ReadAll(rc)
+		if err != nil {
+			return nil, nil, err
+		}
+		form, err := url.ParseQuery(string(body))
+		return req, form, err
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, nil, err
+	}
+	c := req.Clone(req.Context())
+	c.Body = ioutil.NopCloser(bytes.NewReader(body))
+	form, err := url.ParseQuery(string(body))
+	return c, form, err
+}
+
+// scrub returns a copy of v with the values of scrubbed params redacted.
+func scrub(v url.Values) url.Values {
+	c := copyValues(v)
+	for _, k := range scrubbedParams {
+		if _, ok := c[k]; ok {
+			c.Set(k, Redacted)
+		}
+	}
+	return c
+}
+
+// strip returns a copy of v without scrubbed params.
+func strip(v url.Values) url.Values {
+	c := copyValues(v)
+	for _, k := range scrubbedParams {
+		c.Del(k)
+	}
+	return c
+}
+
+func copyValues(v url.Values) url.Values {
+	c := make(url.Values, len(v))
+	for k, vv := range v {
+		c[k] = append([]string(nil), vv...)
+	}
+	return c
+}
@@ -0,0 +1,141 @@
+package cassette_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/recentralized/instagram"
+	"github.com/recentralized/instagram/cassette"
+)
+
+func TestRecordReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/users/self/media/recent", func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, "../testdata/image.json")
+	})
+	mux.HandleFunc("/oauth/access_token", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"access_token":"11073382793.b38ede7.t0ken"}`))
+	})
+	server := httptest.NewServer(mux)
+
+	// Record
+	rec, err := cassette.New(path, cassette.Record)
+	if err != nil {
+		t.Fatalf("New: %s", err)
+	}
+	api := &instagram.API{
+		ClientID:             "c1",
+		ClientSecret:         "shh-s3cret",
+		AccessToken:          "11073382793.b38ede7.t0ken",
+		EnforceSignedRequest: true,
+		BaseURL:              server.URL + "/v1",
+		HTTPClient:           rec.Client(),
+	}
+	oauth := instagram.NewOAuth("c1", "shh-s3cret", "http://localhost/")
+	oauth.TokenURL = server.URL + "/oauth/access_token"
+	oauth.HTTPClient = rec.Client()
+
+	ctx := context.Background()
+	want, err := api.GetRecentMedia(ctx, nil)
+	if err != nil {
+		t.Fatalf("GetRecentMedia: %s", err)
+	}
+	if _, err := oauth.GetAccessToken(ctx, "c0de"); err != nil {
+		t.Fatalf("GetAccessToken: %s", err)
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatalf("Save: %s", err)
+	}
+	server.Close()
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %s", err)
+	}
+	for _, secret := range []string{"t0ken", "shh-s3cret", "c0de", "9d69812c742548b7b797a90819402aa2", "a17e93b657de"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("Cassette contains %q", secret)
+		}
+	}
+
+	// Replay, with a different token and host.
+	play, err := cassette.New(path, cassette.Replay)
+	if err != nil {
+		t.Fatalf("New: %s", err)
+	}
+	api.AccessToken = "other"
+	api.BaseURL = "http://replay.invalid/v1"
+	api.HTTPClient = play.Client()
+	oauth.HTTPClient = play.Client()
+
+	got, err := api.GetRecentMedia(ctx, nil)
+	if err != nil {
+		t.Fatalf("GetRecentMedia: %s", err)
+	}
+	if got.Medias[0].ID != want.Medias[0].ID {
+		t.Errorf("Media ID got %s want %s", got.Medias[0].ID, want.Medias[0].ID)
+	}
+	if _, err := oauth.GetAccessToken(ctx, "other"); err != nil {
+		t.Fatalf("GetAccessToken: %s", err)
+	}
+
+	// Each interaction is replayed once.
+	if _, err := api.GetRecentMedia(ctx, nil); err == nil {
+		t.Errorf("Want error replaying an unrecorded request")
+	}
+}
+
+func TestRoundTripDoesNotModifyRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if got, want := r.PostForm.Get("text"), "hi"; got != want {
+			t.Errorf("Form text got %q want %q", got, want)
+		}
+		w.Write([]byte(`{"meta":{"code":200}}`))
+	}))
+	defer server.Close()
+
+	rec, err := cassette.New(filepath.Join(t.TempDir(), "cassette.json"), cassette.Record)
+	if err != nil {
+		t.Fatalf("New: %s", err)
+	}
+	rec.Transport = server.Client().Transport
+
+	tests := []struct {
+		desc    string
+		getBody bool
+	}{
+		{desc: "with GetBody", getBody: true},
+		{desc: "without GetBody"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			req, err := http.NewRequest("POST", server.URL+"/v1/media/1/comments", strings.NewReader("text=hi"))
+			if err != nil {
+				t.Fatalf("NewRequest: %s", err)
+			}
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			if !tt.getBody {
+				req.GetBody = nil
+				req.Body = ioutil.NopCloser(strings.NewReader("text=hi"))
+			}
+			body := req.Body
+
+			resp, err := rec.RoundTrip(req)
+			if err != nil {
+				t.Fatalf("RoundTrip: %s", err)
+			}
+			resp.Body.Close()
+			if req.Body != body {
+				t.Errorf("RoundTrip replaced the request body")
+			}
+		})
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"flag"
-	"io"
 	"log"
 	"os"
 
@@ -28,14 +27,14 @@ func main() {
 	api := integration.NewAPI()
 	api.KeepRawBody = out == "raw"
 
-	resp, err := api.GetMediaRecentComments(ctx, id)
+	resp, err := api.GetMediaRecentComments(ctx, id, nil)
 	if err != nil {
 		log.Fatalf("GetMediaRecentComments: %s", err)
 	}
 
 	switch out {
 	case "raw":
-		_, err = io.Copy(os.Stdout, api.RawBody)
+		_, err = os.Stdout.Write(resp.Response.RawBody)
 	case "go":
 		pretty.Fprintf(os.Stdout, "%# v", resp)
 	case "json":
@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"flag"
-	"io"
 	"log"
 	"net/url"
 	"os"
@@ -43,7 +42,7 @@ func main() {
 
 	switch out {
 	case "raw":
-		_, err = io.Copy(os.Stdout, api.RawBody)
+		_, err = os.Stdout.Write(resp.Response.RawBody)
 	case "go":
 		pretty.Fprintf(os.Stdout, "%# v", resp)
 	case "json":
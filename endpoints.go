@@ -4,13 +4,14 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"strconv"
 )
 
 // GetSelf returns basic information about the authenticated user.
 // REST API: GET /users/self
 func (api *API) GetSelf(ctx context.Context) (res *UserResponse, err error) {
 	res = new(UserResponse)
-	err = api.get(ctx, "/users/self", nil, res)
+	err = api.get(ctx, "GetSelf", "/users/self", nil, res)
 	return
 }
 
@@ -21,19 +22,118 @@ func (api *API) VerifyCredentials(ctx context.Context) (ok bool, err error) {
 	return err == nil, err
 }
 
+// GetUser returns basic information about a user.
+// REST API: GET /users/{user-id}
+func (api *API) GetUser(ctx context.Context, userID string) (res *UserResponse, err error) {
+	res = new(UserResponse)
+	err = api.get(ctx, "GetUser", fmt.Sprintf("/users/%s", url.PathEscape(userID)), nil, res)
+	return
+}
+
 // GetRecentMedia the most recent media published by the authenticated user. May return a mix of types.
 // REST API: GET /users/self/media/recent
 func (api *API) GetRecentMedia(ctx context.Context, params url.Values) (res *PaginatedMediasResponse, err error) {
 	res = new(PaginatedMediasResponse)
-	err = api.get(ctx, "/users/self/media/recent", params, res)
+	err = api.get(ctx, "GetRecentMedia", "/users/self/media/recent", params, res)
 	return
 }
 
-// GetMediaRecentComments returns a list of recent comments on a media.
+// GetUserRecentMedia returns the most recent media published by a user. May
+// return a mix of types.
+// REST API: GET /users/{user-id}/media/recent
+func (api *API) GetUserRecentMedia(ctx context.Context, userID string, params url.Values) (res *PaginatedMediasResponse, err error) {
+	res = new(PaginatedMediasResponse)
+	err = api.get(ctx, "GetUserRecentMedia", fmt.Sprintf("/users/%s/media/recent", url.PathEscape(userID)), params, res)
+	return
+}
+
+// GetMedia returns a media by ID. Returns ErrNotFound if the media was
+// deleted.
+// REST API: GET /media/{media-id}
+func (api *API) GetMedia(ctx context.Context, mediaID string) (res *MediaResponse, err error) {
+	res = new(MediaResponse)
+	err = api.get(ctx, "GetMedia", fmt.Sprintf("/media/%s", url.PathEscape(mediaID)), nil, res)
+	return
+}
+
+// GetMediaByShortcode returns a media by the shortcode in its link, for
+// instance "Bt39H_1HPI5YVhqU2BTNiI40_wBQerAXmEDoeU0" from
+// https://www.instagram.com/p/Bt39H_1HPI5YVhqU2BTNiI40_wBQerAXmEDoeU0/.
+// Returns ErrNotFound if the media was deleted.
+// REST API: GET /media/shortcode/{shortcode}
+func (api *API) GetMediaByShortcode(ctx context.Context, shortcode string) (res *MediaResponse, err error) {
+	res = new(MediaResponse)
+	err = api.get(ctx, "GetMediaByShortcode", fmt.Sprintf("/media/shortcode/%s", url.PathEscape(shortcode)), nil, res)
+	return
+}
+
+// GetMediaRecentComments returns a list of recent comments on a media. Use
+// params to set the count and cursor.
 // Requires scope: comments.
 // REST API: GET /media/{media-id}/comments
-func (api *API) GetMediaRecentComments(ctx context.Context, mediaID string) (res *CommentsResponse, err error) {
+func (api *API) GetMediaRecentComments(ctx context.Context, mediaID string, params url.Values) (res *CommentsResponse, err error) {
 	res = new(CommentsResponse)
-	err = api.get(ctx, fmt.Sprintf("/media/%s/comments", mediaID), nil, res)
+	err = api.get(ctx, "GetMediaRecentComments", fmt.Sprintf("/media/%s/comments", url.PathEscape(mediaID)), params, res)
+	return
+}
+
+// GetMediaLikes returns the users who have liked a media.
+// Requires scope: public_content.
+// REST API: GET /media/{media-id}/likes
+func (api *API) GetMediaLikes(ctx context.Context, mediaID string) (res *UsersResponse, err error) {
+	res = new(UsersResponse)
+	err = api.get(ctx, "GetMediaLikes", fmt.Sprintf("/media/%s/likes", url.PathEscape(mediaID)), nil, res)
+	return
+}
+
+// SearchUsers returns users by name. A count of zero uses the API's default.
+// REST API: GET /users/search
+func (api *API) SearchUsers(ctx context.Context, query string, count int) (res *UsersResponse, err error) {
+	res = new(UsersResponse)
+	params := url.Values{}
+	params.Set("q", query)
+	if count > 0 {
+		params.Set("count", strconv.Itoa(count))
+	}
+	err = api.get(ctx, "SearchUsers", "/users/search", params, res)
+	return
+}
+
+// PostComment creates a comment on a media.
+// Requires scope: comments.
+// REST API: POST /media/{media-id}/comments
+func (api *API) PostComment(ctx context.Context, mediaID string, text string) (res *EmptyResponse, err error) {
+	res = new(EmptyResponse)
+	params := url.Values{}
+	params.Set("text", text)
+	err = api.post(ctx, "PostComment", fmt.Sprintf("/media/%s/comments", url.PathEscape(mediaID)), params, res)
+	return
+}
+
+// DeleteComment removes a comment either on the authenticated user's media
+// or authored by the authenticated user.
+// Requires scope: comments.
+// REST API: DELETE /media/{media-id}/comments/{comment-id}
+func (api *API) DeleteComment(ctx context.Context, mediaID string, commentID string) (res *EmptyResponse, err error) {
+	res = new(EmptyResponse)
+	err = api.delete(ctx, "DeleteComment", fmt.Sprintf("/media/%s/comments/%s", url.PathEscape(mediaID), url.PathEscape(commentID)), nil, res)
+	return
+}
+
+// LikeMedia sets a like on a media by the authenticated user.
+// Requires scope: likes.
+// REST API: POST /media/{media-id}/likes
+func (api *API) LikeMedia(ctx context.Context, mediaID string) (res *EmptyResponse, err error) {
+	res = new(EmptyResponse)
+	err = api.post(ctx, "LikeMedia", fmt.Sprintf("/media/%s/likes", url.PathEscape(mediaID)), nil, res)
+	return
+}
+
+// UnlikeMedia removes a like on a media by the authenticated user.
+// Requires scope: likes.
+// REST API: DELETE /media/{media-id}/likes
+func (api *API) UnlikeMedia(ctx context.Context, mediaID string) (res *EmptyResponse, err error) {
+	res = new(EmptyResponse)
+	err = api.delete(ctx, "UnlikeMedia", fmt.Sprintf("/media/%s/likes", url.PathEscape(mediaID)), nil, res)
 	return
 }
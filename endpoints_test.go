@@ -2,8 +2,11 @@ package instagram
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"net/url"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -335,7 +338,7 @@ func TestComments(t *testing.T) {
 			defer server.Close()
 
 			ctx := context.Background()
-			resp, err := api.GetMediaRecentComments(ctx, "123")
+			resp, err := api.GetMediaRecentComments(ctx, "123", nil)
 			if err != nil {
 				t.Fatalf("GetMediaRecentComments: %s", err)
 			}
@@ -353,3 +356,393 @@ func TestComments(t *testing.T) {
 		})
 	}
 }
+
+func TestGetUser(t *testing.T) {
+	var path string
+	api, server := newTestAPIServer(t, func(r *http.Request) string {
+		path = r.URL.Path
+		return "user"
+	})
+	defer server.Close()
+
+	ctx := context.Background()
+	resp, err := api.GetUser(ctx, "11073382793")
+	if err != nil {
+		t.Fatalf("GetUser: %s", err)
+	}
+
+	if got, want := path, "/v1/users/11073382793"; got != want {
+		t.Errorf("Path got %s want %s", got, want)
+	}
+
+	want := &User{
+		ID:             "11073382793",
+		Username:       "go_ig_test_0219",
+		FullName:       "Golang Client",
+		ProfilePicture: "https://scontent-sjc3-1.cdninstagram.com/vp/504ac2fa79adb1d412b31cab19be8d36/5CDDD9F1/t51.2885-19/44884218_345707102882519_2446069589734326272_n.jpg?_nc_ht=scontent-sjc3-1.cdninstagram.com",
+		Bio:            "Testing a Go client",
+		Website:        "https://github.com/recentralized/instagram",
+		Counts: &UserCounts{
+			Media:      4,
+			Follows:    1,
+			FollowedBy: 2,
+		},
+	}
+	if got := resp.User; !reflect.DeepEqual(got, want) {
+		t.Errorf("User not equal\nhave: %#v\nwant: %#v", got, want)
+		pretty.Ldiff(t, got, want)
+	}
+}
+
+func TestGetUserRecentMedia(t *testing.T) {
+	var paths []string
+	api, server := newTestAPIServer(t, func(r *http.Request) string {
+		paths = append(paths, r.URL.Path)
+		switch len(paths) {
+		case 1:
+			return "image"
+		case 2:
+			return "video"
+		}
+		return "media_empty"
+	})
+	defer server.Close()
+
+	ctx := context.Background()
+	resp, err := api.GetUserRecentMedia(ctx, "11073382793", nil)
+	if err != nil {
+		t.Fatalf("GetUserRecentMedia: %s", err)
+	}
+
+	var ids []string
+	mediac, errc := api.IterateMedia(ctx, resp)
+	for m := range mediac {
+		ids = append(ids, m.ID)
+	}
+	for err := range errc {
+		t.Fatalf("IterateMedia: %s", err)
+	}
+
+	wantIDs := []string{
+		"1979320569926821011_11073382793",
+		"1979318157757411422_11073382793",
+	}
+	if !reflect.DeepEqual(ids, wantIDs) {
+		t.Errorf("IDs got %v want %v", ids, wantIDs)
+	}
+
+	wantPaths := []string{
+		"/v1/users/11073382793/media/recent",
+		"/v1/users/11073382793/media/recent",
+		"/v1/users/11073382793/media/recent",
+	}
+	if !reflect.DeepEqual(paths, wantPaths) {
+		t.Errorf("Paths got %v want %v", paths, wantPaths)
+	}
+}
+
+func TestGetMedia(t *testing.T) {
+	api, server := newTestAPIServer(t, func(r *http.Request) string {
+		switch r.URL.Path {
+		case "/v1/users/self/media/recent":
+			return "carousel_mixed"
+		case "/v1/media/1979319391662961209_11073382793",
+			"/v1/media/shortcode/Bt39H_1HPI5YVhqU2BTNiI40_wBQerAXmEDoeU0":
+			return "media"
+		}
+		return "not_found"
+	})
+	defer server.Close()
+
+	ctx := context.Background()
+	recent, err := api.GetRecentMedia(ctx, nil)
+	if err != nil {
+		t.Fatalf("GetRecentMedia: %s", err)
+	}
+	want := recent.Medias[0]
+
+	resp, err := api.GetMedia(ctx, "1979319391662961209_11073382793")
+	if err != nil {
+		t.Fatalf("GetMedia: %s", err)
+	}
+	if got := *resp.Media; !reflect.DeepEqual(got, want) {
+		t.Errorf("Media not equal\nhave: %#v\nwant: %#v", got, want)
+		pretty.Ldiff(t, got, want)
+	}
+
+	resp, err = api.GetMediaByShortcode(ctx, "Bt39H_1HPI5YVhqU2BTNiI40_wBQerAXmEDoeU0")
+	if err != nil {
+		t.Fatalf("GetMediaByShortcode: %s", err)
+	}
+	if got := *resp.Media; !reflect.DeepEqual(got, want) {
+		t.Errorf("Media not equal\nhave: %#v\nwant: %#v", got, want)
+		pretty.Ldiff(t, got, want)
+	}
+
+	_, err = api.GetMedia(ctx, "123_456")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Want ErrNotFound got %v", err)
+	}
+	_, err = api.GetMediaByShortcode(ctx, "deleted")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Want ErrNotFound got %v", err)
+	}
+}
+
+func TestIterateComments(t *testing.T) {
+	var queries []string
+	api, server := newTestAPIServer(t, func(r *http.Request) string {
+		q := r.URL.Query()
+		queries = append(queries, "count="+q.Get("count")+" cursor="+q.Get("cursor"))
+		if len(queries) == 1 {
+			return "comments_page"
+		}
+		return "comments"
+	})
+	defer server.Close()
+
+	ctx := context.Background()
+	resp, err := api.GetMediaRecentComments(ctx, "1979320569926821011_11073382793", url.Values{"count": {"1"}})
+	if err != nil {
+		t.Fatalf("GetMediaRecentComments: %s", err)
+	}
+
+	var ids []string
+	commentc, errc := api.IterateComments(ctx, resp)
+	for c := range commentc {
+		ids = append(ids, c.ID)
+	}
+	for err := range errc {
+		t.Fatalf("IterateComments: %s", err)
+	}
+
+	wantIDs := []string{
+		"18026312770133409",
+		"18034730665003447",
+		"18017490472103037",
+		"18018896065110204",
+	}
+	if !reflect.DeepEqual(ids, wantIDs) {
+		t.Errorf("IDs got %v want %v", ids, wantIDs)
+	}
+
+	wantQueries := []string{
+		"count=1 cursor=",
+		"count=1 cursor=18034730665003447",
+	}
+	if !reflect.DeepEqual(queries, wantQueries) {
+		t.Errorf("Queries got %v want %v", queries, wantQueries)
+	}
+}
+
+func TestGetMediaRecentCommentsEscapesID(t *testing.T) {
+	var path string
+	api, server := newTestAPIServer(t, func(r *http.Request) string {
+		path = r.URL.EscapedPath()
+		return "comments"
+	})
+	defer server.Close()
+
+	if _, err := api.GetMediaRecentComments(context.Background(), "1/2?3", nil); err != nil {
+		t.Fatalf("GetMediaRecentComments: %s", err)
+	}
+	if got, want := path, "/v1/media/1%2F2%3F3/comments"; got != want {
+		t.Errorf("Path got %s want %s", got, want)
+	}
+}
+
+func TestIterateCommentsCancel(t *testing.T) {
+	api, server := newTestAPIServer(t, func(r *http.Request) string {
+		return "comments"
+	})
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	resp, err := api.GetMediaRecentComments(ctx, "123", nil)
+	if err != nil {
+		t.Fatalf("GetMediaRecentComments: %s", err)
+	}
+
+	var count int
+	commentc, errc := api.IterateComments(ctx, resp)
+	for range commentc {
+		count++
+		cancel()
+	}
+	for err := range errc {
+		t.Fatalf("IterateComments: %s", err)
+	}
+	if count > 2 {
+		t.Errorf("Iteration continued after cancel: %d comments", count)
+	}
+}
+
+func TestWriteEndpoints(t *testing.T) {
+	type request struct {
+		method string
+		path   string
+		params url.Values
+	}
+	var got request
+
+	api, server := newTestAPIServer(t, func(r *http.Request) string {
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("ParseForm: %s", err)
+		}
+		params := r.URL.Query()
+		if r.Method == "POST" {
+			if got, want := r.Header.Get("Content-Type"), "application/x-www-form-urlencoded"; got != want {
+				t.Errorf("Content-Type got %s want %s", got, want)
+			}
+			if r.URL.RawQuery != "" {
+				t.Errorf("POST has query %s", r.URL.RawQuery)
+			}
+			params = r.PostForm
+		}
+
+		// The signature must cover every param, including the body.
+		path := strings.TrimPrefix(r.URL.Path, "/v1")
+		sig := params.Get("sig")
+		params.Del("sig")
+		want := signParams(path, copyParams(params), "s3cret").Get("sig")
+		if sig != want {
+			t.Errorf("%s %s sig got %s want %s", r.Method, path, sig, want)
+		}
+
+		got = request{r.Method, path, params}
+		return "empty"
+	})
+	defer server.Close()
+	api.AccessToken = "t0ken"
+	api.ClientSecret = "s3cret"
+
+	tests := []struct {
+		desc string
+		call func(ctx context.Context) (*EmptyResponse, error)
+		want request
+	}{
+		{
+			desc: "post comment",
+			call: func(ctx context.Context) (*EmptyResponse, error) {
+				return api.PostComment(ctx, "123_456", "Nice! #0219test")
+			},
+			want: request{"POST", "/media/123_456/comments", url.Values{
+				"access_token": {"t0ken"},
+				"text":         {"Nice! #0219test"},
+			}},
+		},
+		{
+			desc: "delete comment",
+			call: func(ctx context.Context) (*EmptyResponse, error) {
+				return api.DeleteComment(ctx, "123_456", "789")
+			},
+			want: request{"DELETE", "/media/123_456/comments/789", url.Values{
+				"access_token": {"t0ken"},
+			}},
+		},
+		{
+			desc: "like",
+			call: func(ctx context.Context) (*EmptyResponse, error) {
+				return api.LikeMedia(ctx, "123_456")
+			},
+			want: request{"POST", "/media/123_456/likes", url.Values{
+				"access_token": {"t0ken"},
+			}},
+		},
+		{
+			desc: "unlike",
+			call: func(ctx context.Context) (*EmptyResponse, error) {
+				return api.UnlikeMedia(ctx, "123_456")
+			},
+			want: request{"DELETE", "/media/123_456/likes", url.Values{
+				"access_token": {"t0ken"},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			res, err := tt.call(context.Background())
+			if err != nil {
+				t.Fatalf("%s: %s", tt.desc, err)
+			}
+			if got, want := res.Meta.Code, 200; got != want {
+				t.Errorf("Code got %d want %d", got, want)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Request got %+v want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetMediaLikes(t *testing.T) {
+	var requests []string
+	api, server := newTestAPIServer(t, func(r *http.Request) string {
+		requests = append(requests, r.URL.Path+" cursor="+r.URL.Query().Get("cursor"))
+		if len(requests) == 1 {
+			return "likes"
+		}
+		return "users_search"
+	})
+	defer server.Close()
+
+	ctx := context.Background()
+	resp, err := api.GetMediaLikes(ctx, "1979320569926821011_11073382793")
+	if err != nil {
+		t.Fatalf("GetMediaLikes: %s", err)
+	}
+
+	var users []User
+	userc, errc := api.IterateUsers(ctx, resp)
+	for u := range userc {
+		users = append(users, *u)
+	}
+	for err := range errc {
+		t.Fatalf("IterateUsers: %s", err)
+	}
+
+	wantUsers := []User{
+		{Username: "rcarver"},
+		{
+			ID:             "11073382793",
+			Username:       "go_ig_test_0219",
+			FullName:       "Golang Client",
+			ProfilePicture: "https://scontent-sjc3-1.cdninstagram.com/vp/504ac2fa79adb1d412b31cab19be8d36/5CDDD9F1/t51.2885-19/44884218_345707102882519_2446069589734326272_n.jpg?_nc_ht=scontent-sjc3-1.cdninstagram.com",
+		},
+		{Username: "rcarver"},
+	}
+	if !reflect.DeepEqual(users, wantUsers) {
+		t.Errorf("Users not equal\nhave: %#v\nwant: %#v", users, wantUsers)
+		pretty.Ldiff(t, users, wantUsers)
+	}
+
+	wantRequests := []string{
+		"/v1/media/1979320569926821011_11073382793/likes cursor=",
+		"/v1/media/1979320569926821011_11073382793/likes cursor=rcarver",
+	}
+	if !reflect.DeepEqual(requests, wantRequests) {
+		t.Errorf("Requests got %v want %v", requests, wantRequests)
+	}
+}
+
+func TestSearchUsers(t *testing.T) {
+	var query url.Values
+	api, server := newTestAPIServer(t, func(r *http.Request) string {
+		query = r.URL.Query()
+		return "users_search"
+	})
+	defer server.Close()
+
+	resp, err := api.SearchUsers(context.Background(), "go_ig", 2)
+	if err != nil {
+		t.Fatalf("SearchUsers: %s", err)
+	}
+	if got, want := len(resp.Users), 2; got != want {
+		t.Errorf("Users got %d want %d", got, want)
+	}
+	if got, want := query.Get("q")+" "+query.Get("count"), "go_ig 2"; got != want {
+		t.Errorf("Query got %s want %s", got, want)
+	}
+}
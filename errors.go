@@ -0,0 +1,141 @@
+package instagram
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Errors returned by the API can be tested against these with errors.Is.
+var (
+	// ErrInvalidToken means the access token or client ID is missing,
+	// invalid or expired.
+	ErrInvalidToken = errors.New("instagram: invalid access token")
+
+	// ErrRateLimited means the quota has been exhausted.
+	ErrRateLimited = errors.New("instagram: rate limited")
+
+	// ErrNotFound means the requested object does not exist, or was
+	// deleted.
+	ErrNotFound = errors.New("instagram: not found")
+
+	// ErrPermissionDenied means the token lacks the scope or permission
+	// for the request.
+	ErrPermissionDenied = errors.New("instagram: permission denied")
+)
+
+// errorTypes maps the error_type of response metadata to the sentinel
+// errors.
+var errorTypes = map[string]error{
+	"OAuthAccessTokenException": ErrInvalidToken,
+	"OAuthParameterException":   ErrInvalidToken,
+	"OAuthRateLimitException":   ErrRateLimited,
+	"APINotFoundError":          ErrNotFound,
+	"OAuthPermissionsException": ErrPermissionDenied,
+	"OAuthForbiddenException":   ErrPermissionDenied,
+	"APINotAllowedError":        ErrPermissionDenied,
+}
+
+// MetaError is an error from response metadata. Use errors.Is with the Err*
+// values to check what kind of error it is.
+type MetaError Meta
+
+func (m *MetaError) Error() string {
+	return fmt.Sprintf("Error making api call: Code %d %s %s", m.Code, m.ErrorType, m.ErrorMessage)
+}
+
+// Is returns true if target is the sentinel error for the error type.
+func (m *MetaError) Is(target error) bool {
+	return target != nil && m.sentinel() == target
+}
+
+func (m *MetaError) sentinel() error {
+	if err, ok := errorTypes[m.ErrorType]; ok {
+		return err
+	}
+	switch m.Code {
+	case http.StatusUnauthorized:
+		return ErrInvalidToken
+	case http.StatusForbidden:
+		return ErrPermissionDenied
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	}
+	return nil
+}
+
+// Temporary returns true if the error is caused by a server side problem
+// that may go away on its own.
+func (m *MetaError) Temporary() bool {
+	return m.Code >= 500
+}
+
+// Retryable returns true if the same request may succeed later.
+func (m *MetaError) Retryable() bool {
+	return m.Temporary() || m.Is(ErrRateLimited)
+}
+
+// GraphError is an error from the Graph API. Use errors.Is with the Err*
+// values to check what kind of error it is.
+type GraphError struct {
+	// StatusCode is the HTTP status of the response.
+	StatusCode int `json:"-"`
+
+	Message      string `json:"message"`
+	Type         string `json:"type"`
+	Code         int    `json:"code"`
+	ErrorSubcode int    `json:"error_subcode"`
+	IsTransient  bool   `json:"is_transient"`
+	FBTraceID    string `json:"fbtrace_id"`
+}
+
+func (e *GraphError) Error() string {
+	return fmt.Sprintf("Error making graph api call: Code %d %s %s", e.Code, e.Type, e.Message)
+}
+
+// Is returns true if target is the sentinel error for the error code.
+func (e *GraphError) Is(target error) bool {
+	return target != nil && e.sentinel() == target
+}
+
+func (e *GraphError) sentinel() error {
+	switch {
+	case e.Code == 190 || e.Code == 102:
+		return ErrInvalidToken
+	case e.Code == 4 || e.Code == 17 || e.Code == 32 || e.Code == 613:
+		return ErrRateLimited
+	case e.Code == 100 && e.ErrorSubcode == 33, e.Code == 803:
+		return ErrNotFound
+	case e.Code == 10 || (e.Code >= 200 && e.Code <= 299):
+		return ErrPermissionDenied
+	}
+	m := MetaError{Code: e.StatusCode}
+	return m.sentinel()
+}
+
+// Temporary returns true if the error is caused by a server side problem
+// that may go away on its own.
+func (e *GraphError) Temporary() bool {
+	return e.IsTransient || e.Code == 1 || e.Code == 2 || e.StatusCode >= 500
+}
+
+// Retryable returns true if the same request may succeed later.
+func (e *GraphError) Retryable() bool {
+	return e.Temporary() || e.Is(ErrRateLimited)
+}
+
+// DecodeError is returned when a response body can't be decoded.
+type DecodeError struct {
+	Err error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("instagram: error decoding body; %s", e.Err)
+}
+
+// Unwrap returns the underlying decoding error.
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
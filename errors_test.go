@@ -0,0 +1,138 @@
+package instagram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestMetaErrorIs(t *testing.T) {
+	sentinels := []error{
+		ErrInvalidToken,
+		ErrRateLimited,
+		ErrNotFound,
+		ErrPermissionDenied,
+	}
+	tests := []struct {
+		desc          string
+		err           *MetaError
+		want          error
+		wantRetryable bool
+		wantTemporary bool
+	}{
+		{
+			desc: "access token",
+			err:  &MetaError{Code: 400, ErrorType: "OAuthAccessTokenException"},
+			want: ErrInvalidToken,
+		},
+		{
+			desc:          "rate limit",
+			err:           &MetaError{Code: 429, ErrorType: "OAuthRateLimitException"},
+			want:          ErrRateLimited,
+			wantRetryable: true,
+		},
+		{
+			desc: "not found",
+			err:  &MetaError{Code: 400, ErrorType: "APINotFoundError"},
+			want: ErrNotFound,
+		},
+		{
+			desc: "permissions",
+			err:  &MetaError{Code: 400, ErrorType: "OAuthPermissionsException"},
+			want: ErrPermissionDenied,
+		},
+		{
+			desc:          "status only",
+			err:           &MetaError{Code: 429},
+			want:          ErrRateLimited,
+			wantRetryable: true,
+		},
+		{
+			desc:          "server error",
+			err:           &MetaError{Code: 502, ErrorMessage: "502 Bad Gateway"},
+			wantRetryable: true,
+			wantTemporary: true,
+		},
+		{
+			desc: "unknown",
+			err:  &MetaError{Code: 400, ErrorType: "APIInvalidParametersError"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			err := fmt.Errorf("wrapped: %w", tt.err)
+			for _, s := range sentinels {
+				if got, want := errors.Is(err, s), s == tt.want; got != want {
+					t.Errorf("errors.Is(%v) got %t want %t", s, got, want)
+				}
+			}
+			if got, want := tt.err.Retryable(), tt.wantRetryable; got != want {
+				t.Errorf("Retryable got %t want %t", got, want)
+			}
+			if got, want := tt.err.Temporary(), tt.wantTemporary; got != want {
+				t.Errorf("Temporary got %t want %t", got, want)
+			}
+		})
+	}
+}
+
+func TestAPIErrors(t *testing.T) {
+	api, server := newTestAPIServer(t, func(r *http.Request) string {
+		return "invalid_token"
+	})
+	defer server.Close()
+
+	res, err := api.GetSelf(context.Background())
+	if !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("Want ErrInvalidToken got %v", err)
+	}
+	var m *MetaError
+	if !errors.As(err, &m) {
+		t.Fatalf("Want MetaError got %T", err)
+	}
+	if got, want := m.ErrorMessage, "The access_token provided is invalid."; got != want {
+		t.Errorf("ErrorMessage got %q want %q", got, want)
+	}
+	if got, want := res.Response.StatusCode, 400; got != want {
+		t.Errorf("StatusCode got %d want %d", got, want)
+	}
+}
+
+func TestDecodeError(t *testing.T) {
+	mux, server := initTestServer()
+	defer server.Close()
+
+	mux.HandleFunc("/v1/users/self", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html>`)
+	})
+	mux.HandleFunc("/v1/users/self/media/recent", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		fmt.Fprint(w, `<html>`)
+	})
+
+	api := &API{
+		BaseURL:    server.URL + "/v1",
+		HTTPClient: server.Client(),
+	}
+	ctx := context.Background()
+
+	_, err := api.GetSelf(ctx)
+	var d *DecodeError
+	if !errors.As(err, &d) {
+		t.Errorf("Want DecodeError got %T %v", err, err)
+	}
+
+	_, err = api.GetRecentMedia(ctx, nil)
+	var m *MetaError
+	if !errors.As(err, &m) {
+		t.Fatalf("Want MetaError got %T %v", err, err)
+	}
+	if got, want := m.Code, http.StatusBadGateway; got != want {
+		t.Errorf("Code got %d want %d", got, want)
+	}
+	if !m.Temporary() {
+		t.Errorf("Want Temporary")
+	}
+}
@@ -0,0 +1,199 @@
+package instagram
+
+import "strings"
+
+// MediaField is a field of a media in the Graph API. The Graph API returns
+// only the fields that are requested. Each field documents what it populates
+// in the result.
+type MediaField struct {
+	name   string
+	nested []MediaField
+}
+
+// Expand returns the field with nested fields, for instance
+// MediaFieldChildren.Expand(MediaFieldID, MediaFieldMediaURL).
+func (f MediaField) Expand(fields ...MediaField) MediaField {
+	return MediaField{name: f.name, nested: append([]MediaField(nil), fields...)}
+}
+
+// String returns the field as the fields param expects.
+func (f MediaField) String() string {
+	if len(f.nested) == 0 {
+		return f.name
+	}
+	return f.name + "{" + MediaFields(f.nested).String() + "}"
+}
+
+// MediaFields is a set of media fields to request.
+type MediaFields []MediaField
+
+// String returns the value of the fields param, for instance
+// "id,caption,children{id,media_url}".
+func (fs MediaFields) String() string {
+	names := make([]string, len(fs))
+	for i, f := range fs {
+		names[i] = f.String()
+	}
+	return strings.Join(names, ",")
+}
+
+// UserField is a field of a user in the Graph API, like MediaField.
+type UserField struct {
+	name string
+}
+
+// String returns the field as the fields param expects.
+func (f UserField) String() string {
+	return f.name
+}
+
+// UserFields is a set of user fields to request.
+type UserFields []UserField
+
+// String returns the value of the fields param, for instance
+// "id,username".
+func (fs UserFields) String() string {
+	names := make([]string, len(fs))
+	for i, f := range fs {
+		names[i] = f.String()
+	}
+	return strings.Join(names, ",")
+}
+
+// mediaFieldsParam returns the fields param for fields, or for def if none
+// are given.
+func mediaFieldsParam(fields []MediaField, def []MediaField) string {
+	if len(fields) == 0 {
+		return MediaFields(def).String()
+	}
+	return MediaFields(fields).String()
+}
+
+// userFieldsParam is mediaFieldsParam for user fields.
+func userFieldsParam(fields []UserField, def []UserField) string {
+	if len(fields) == 0 {
+		return UserFields(def).String()
+	}
+	return UserFields(fields).String()
+}
+
+// Fields of a media, for Graph GetMedia, GetMyMedia, GetMediaChildren and
+// the hashtag media endpoints.
+var (
+	// MediaFieldID populates Media.ID.
+	MediaFieldID = MediaField{name: "id"}
+
+	// MediaFieldCaption populates Media.Caption.Text.
+	MediaFieldCaption = MediaField{name: "caption"}
+
+	// MediaFieldMediaType populates Media.Type and CarouselMedia.Type,
+	// and determines whether the media URL is an image or a video.
+	MediaFieldMediaType = MediaField{name: "media_type"}
+
+	// MediaFieldMediaURL populates the StandardResolution of Media.Images
+	// or, for videos, Media.Videos. Requires MediaFieldMediaType.
+	MediaFieldMediaURL = MediaField{name: "media_url"}
+
+	// MediaFieldPermalink populates Media.Link.
+	MediaFieldPermalink = MediaField{name: "permalink"}
+
+	// MediaFieldThumbnailURL populates Media.Images.StandardResolution of
+	// videos.
+	MediaFieldThumbnailURL = MediaField{name: "thumbnail_url"}
+
+	// MediaFieldTimestamp populates Media.CreatedTime and
+	// Media.Caption.CreatedTime.
+	MediaFieldTimestamp = MediaField{name: "timestamp"}
+
+	// MediaFieldUsername populates Media.User.Username.
+	MediaFieldUsername = MediaField{name: "username"}
+
+	// MediaFieldLikeCount populates Media.Likes.Count. Business and
+	// creator accounts only.
+	MediaFieldLikeCount = MediaField{name: "like_count"}
+
+	// MediaFieldCommentsCount populates Media.Comments.Count. Business and
+	// creator accounts only.
+	MediaFieldCommentsCount = MediaField{name: "comments_count"}
+
+	// MediaFieldChildren populates Media.CarouselMedias of carousels. Use
+	// Expand to choose the fields of the children, which are populated
+	// like those of a media.
+	MediaFieldChildren = MediaField{name: "children"}
+)
+
+// Fields of a user, for Graph GetMe.
+var (
+	// UserFieldID populates User.ID.
+	UserFieldID = UserField{name: "id"}
+
+	// UserFieldUsername populates User.Username.
+	UserFieldUsername = UserField{name: "username"}
+
+	// UserFieldMediaCount populates User.Counts.Media.
+	UserFieldMediaCount = UserField{name: "media_count"}
+)
+
+var (
+	defaultChildFields = MediaFields{
+		MediaFieldID,
+		MediaFieldMediaType,
+		MediaFieldMediaURL,
+		MediaFieldThumbnailURL,
+	}
+
+	defaultMediaFields = MediaFields{
+		MediaFieldID,
+		MediaFieldCaption,
+		MediaFieldMediaType,
+		MediaFieldMediaURL,
+		MediaFieldPermalink,
+		MediaFieldThumbnailURL,
+		MediaFieldTimestamp,
+		MediaFieldUsername,
+		MediaFieldChildren.Expand(defaultChildFields...),
+	}
+
+	defaultHashtagMediaFields = MediaFields{
+		MediaFieldID,
+		MediaFieldCaption,
+		MediaFieldMediaType,
+		MediaFieldMediaURL,
+		MediaFieldPermalink,
+		MediaFieldTimestamp,
+		MediaFieldLikeCount,
+		MediaFieldCommentsCount,
+		MediaFieldChildren.Expand(MediaFieldID, MediaFieldMediaType, MediaFieldMediaURL),
+	}
+
+	defaultUserFields = UserFields{
+		UserFieldID,
+		UserFieldUsername,
+		UserFieldMediaCount,
+	}
+)
+
+// DefaultChildFields returns the fields requested for the children of a
+// carousel when no fields are given.
+func DefaultChildFields() MediaFields {
+	return append(MediaFields(nil), defaultChildFields...)
+}
+
+// DefaultMediaFields returns the fields requested for media when no fields
+// are given.
+func DefaultMediaFields() MediaFields {
+	return append(MediaFields(nil), defaultMediaFields...)
+}
+
+// DefaultHashtagMediaFields returns the fields requested for the media of
+// hashtags when no fields are given. The owner of hashtag media isn't
+// available.
+func DefaultHashtagMediaFields() MediaFields {
+	return append(MediaFields(nil), defaultHashtagMediaFields...)
+}
+
+// DefaultUserFields returns the fields requested for users when no fields
+// are given.
+func DefaultUserFields() UserFields {
+	return append(UserFields(nil), defaultUserFields...)
+}
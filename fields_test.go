@@ -0,0 +1,94 @@
+package instagram
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestFieldsString(t *testing.T) {
+	tests := []struct {
+		desc   string
+		fields MediaFields
+		want   string
+	}{
+		{
+			desc:   "empty",
+			fields: nil,
+			want:   "",
+		},
+		{
+			desc:   "flat",
+			fields: MediaFields{MediaFieldID, MediaFieldCaption},
+			want:   "id,caption",
+		},
+		{
+			desc:   "expanded",
+			fields: MediaFields{MediaFieldID, MediaFieldChildren.Expand(MediaFieldID, MediaFieldMediaURL)},
+			want:   "id,children{id,media_url}",
+		},
+		{
+			desc:   "not expanded",
+			fields: MediaFields{MediaFieldChildren},
+			want:   "children",
+		},
+		{
+			desc:   "default media",
+			fields: DefaultMediaFields(),
+			want:   "id,caption,media_type,media_url,permalink,thumbnail_url,timestamp,username,children{id,media_type,media_url,thumbnail_url}",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if got := tt.fields.String(); got != tt.want {
+				t.Errorf("String got %q want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFieldExpandDoesNotModify(t *testing.T) {
+	MediaFieldChildren.Expand(MediaFieldID)
+	if got, want := MediaFieldChildren.String(), "children"; got != want {
+		t.Errorf("String got %q want %q", got, want)
+	}
+}
+
+func TestUserFieldsString(t *testing.T) {
+	if got, want := DefaultUserFields().String(), "id,username,media_count"; got != want {
+		t.Errorf("String got %q want %q", got, want)
+	}
+}
+
+func TestDefaultFieldsAreCopies(t *testing.T) {
+	fields := DefaultMediaFields()
+	fields[0] = MediaFieldCaption
+	if got, want := DefaultMediaFields()[0], MediaFieldID; got.String() != want.String() {
+		t.Errorf("Default got %s want %s", got, want)
+	}
+	users := DefaultUserFields()
+	users[0] = UserFieldUsername
+	if got, want := DefaultUserFields()[0], UserFieldID; got != want {
+		t.Errorf("Default got %s want %s", got, want)
+	}
+}
+
+func TestGraphFields(t *testing.T) {
+	var fields string
+	g, server := newTestGraphServer(t, func(r *http.Request) string {
+		fields = r.URL.Query().Get("fields")
+		return "graph_media"
+	})
+	defer server.Close()
+
+	res, err := g.GetMedia(context.Background(), "17895695668004550", MediaFieldID, MediaFieldPermalink)
+	if err != nil {
+		t.Fatalf("GetMedia: %s", err)
+	}
+	if got, want := fields, "id,permalink"; got != want {
+		t.Errorf("fields got %q want %q", got, want)
+	}
+	if got, want := res.Media.Link, "https://www.instagram.com/p/Bt39H_1HPI5YVhqU2BTNiI40_wBQerAXmEDoeU0/"; got != want {
+		t.Errorf("Link got %q want %q", got, want)
+	}
+}
@@ -1,3 +1,7 @@
 module github.com/recentralized/instagram
 
+go 1.24
+
 require github.com/kr/pretty v0.1.0
+
+require github.com/kr/text v0.1.0 // indirect
@@ -0,0 +1,346 @@
+package instagram
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Graph is a client for the Instagram Graph API, which replaces the retired
+// API. It shares the access token, HTTP client, retries, rate limiting,
+// interceptors, cache and logger of its API, and returns the same errors.
+// Results are mapped into the same types as the API, such as Media and User.
+type Graph struct {
+	// HashtagBudget, if set, refuses hashtag searches over the weekly
+	// limit of each account before they are made.
+	HashtagBudget *HashtagBudget
+
+	api *API
+}
+
+// NewGraph returns a Graph that makes requests with api's configuration.
+// Requests go to api.GraphBaseURL and require an access token.
+func NewGraph(api *API) *Graph {
+	return &Graph{api: api}
+}
+
+// GetMe returns the user of the access token, with fields or
+// DefaultUserFields.
+// Graph API: GET /me
+func (g *Graph) GetMe(ctx context.Context, fields ...UserField) (res *GraphUserResponse, err error) {
+	res = new(GraphUserResponse)
+	params := url.Values{}
+	params.Set("fields", userFieldsParam(fields, defaultUserFields))
+	err = g.get(ctx, "GetMe", "/me", params, res)
+	return
+}
+
+// GetMyMedia returns the media of the user of the access token, newest
+// first, with fields or DefaultMediaFields. Params may be PageParams.Values.
+// Graph API: GET /me/media
+func (g *Graph) GetMyMedia(ctx context.Context, params url.Values, fields ...MediaField) (res *GraphMediasResponse, err error) {
+	res = new(GraphMediasResponse)
+	params = copyParams(params)
+	params.Set("fields", mediaFieldsParam(fields, defaultMediaFields))
+	err = g.get(ctx, "GetMyMedia", "/me/media", params, res)
+	return
+}
+
+// GetMedia returns a media by ID, with fields or DefaultMediaFields. Returns
+// ErrNotFound if the media was deleted.
+// Graph API: GET /{media-id}
+func (g *Graph) GetMedia(ctx context.Context, mediaID string, fields ...MediaField) (res *GraphMediaResponse, err error) {
+	res = new(GraphMediaResponse)
+	params := url.Values{}
+	params.Set("fields", mediaFieldsParam(fields, defaultMediaFields))
+	err = g.get(ctx, "GetMedia", "/"+url.PathEscape(mediaID), params, res)
+	return
+}
+
+// GetMediaChildren returns the images and videos of a carousel, with fields
+// or DefaultChildFields.
+// Graph API: GET /{media-id}/children
+func (g *Graph) GetMediaChildren(ctx context.Context, mediaID string, fields ...MediaField) (res *GraphChildrenResponse, err error) {
+	res = new(GraphChildrenResponse)
+	params := url.Values{}
+	params.Set("fields", mediaFieldsParam(fields, defaultChildFields))
+	err = g.get(ctx, "GetMediaChildren", fmt.Sprintf("/%s/children", url.PathEscape(mediaID)), params, res)
+	return
+}
+
+func (g *Graph) get(ctx context.Context, endpoint string, path string, params url.Values, r interface{}) error {
+	return g.request(ctx, "GET", endpoint, path, params, r)
+}
+
+func (g *Graph) post(ctx context.Context, endpoint string, path string, params url.Values, r interface{}) error {
+	return g.request(ctx, "POST", endpoint, path, params, r)
+}
+
+func (g *Graph) request(ctx context.Context, method string, endpoint string, path string, params url.Values, r interface{}) error {
+	return g.execute(ctx, &Request{
+		Endpoint: endpoint,
+		Method:   method,
+		Path:     path,
+		Params:   params,
+		Result:   r,
+	})
+}
+
+// execute sends req to the Graph API.
+func (g *Graph) execute(ctx context.Context, req *Request) error {
+	req.Endpoint = "Graph." + req.Endpoint
+	req.Params = ensureParams(req.Params)
+	req.graph = true
+	return g.api.execute(ctx, req)
+}
+
+// sendGraph is send for requests to the Graph API.
+func (api *API) sendGraph(ctx context.Context, req *Request) error {
+	token, err := api.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+	if token == "" {
+		return errors.New("instagram: the Graph API requires an access token")
+	}
+	params := copyParams(req.Params)
+	params.Set("access_token", token)
+
+	httpReq, err := buildRequest(req.Method, api.graphBaseURL()+req.Path, params)
+	if err != nil {
+		return err
+	}
+	if err := api.sendHTTP(ctx, req, httpReq); err != nil {
+		return err
+	}
+	setPageBaseURL(req.Result, api.graphBaseURL())
+	return nil
+}
+
+type graphResponse struct {
+	// Response is metadata about the HTTP response to this call.
+	Response *Response `json:"-"`
+}
+
+func (g *graphResponse) setResponse(r *Response) {
+	g.Response = r
+}
+
+// GraphUserResponse is the Graph API response for GetMe()
+type GraphUserResponse struct {
+	graphResponse
+	User *User
+}
+
+// UnmarshalJSON implements JSON.
+func (r *GraphUserResponse) UnmarshalJSON(in []byte) error {
+	var u graphUser
+	if err := json.Unmarshal(in, &u); err != nil {
+		return err
+	}
+	r.User = u.user()
+	return nil
+}
+
+// GraphMediaResponse is the Graph API response for GetMedia()
+type GraphMediaResponse struct {
+	graphResponse
+	Media *Media
+}
+
+// UnmarshalJSON implements JSON.
+func (r *GraphMediaResponse) UnmarshalJSON(in []byte) error {
+	var m graphMedia
+	if err := json.Unmarshal(in, &m); err != nil {
+		return err
+	}
+	media, err := m.media()
+	if err != nil {
+		return err
+	}
+	r.Media = &media
+	return nil
+}
+
+// GraphMediasResponse is the Graph API response for GetMyMedia()
+type GraphMediasResponse struct {
+	graphResponse
+	Medias     []Media
+	Pagination MediaPagination
+}
+
+func (r *GraphMediasResponse) pagination() *Pagination {
+	return &r.Pagination.Pagination
+}
+
+// UnmarshalJSON implements JSON.
+func (r *GraphMediasResponse) UnmarshalJSON(in []byte) error {
+	var page struct {
+		Data   []graphMedia `json:"data"`
+		Paging graphPaging  `json:"paging"`
+	}
+	if err := json.Unmarshal(in, &page); err != nil {
+		return err
+	}
+	r.Pagination = MediaPagination{page.Paging.pagination()}
+	r.Medias = make([]Media, len(page.Data))
+	for i, m := range page.Data {
+		media, err := m.media()
+		if err != nil {
+			return err
+		}
+		r.Medias[i] = media
+	}
+	return nil
+}
+
+// GraphChildrenResponse is the Graph API response for GetMediaChildren()
+type GraphChildrenResponse struct {
+	graphResponse
+	CarouselMedias []CarouselMedia
+}
+
+// UnmarshalJSON implements JSON.
+func (r *GraphChildrenResponse) UnmarshalJSON(in []byte) error {
+	var page struct {
+		Data []graphMedia `json:"data"`
+	}
+	if err := json.Unmarshal(in, &page); err != nil {
+		return err
+	}
+	r.CarouselMedias = make([]CarouselMedia, len(page.Data))
+	for i, m := range page.Data {
+		r.CarouselMedias[i] = m.carouselMedia()
+	}
+	return nil
+}
+
+// graphPaging is the paging of Graph API lists.
+type graphPaging struct {
+	Cursors struct {
+		Before string `json:"before"`
+		After  string `json:"after"`
+	} `json:"cursors"`
+	Next     string `json:"next"`
+	Previous string `json:"previous"`
+}
+
+func (p graphPaging) pagination() Pagination {
+	return Pagination{
+		NextURL:     p.Next,
+		PreviousURL: p.Previous,
+		Before:      p.Cursors.Before,
+		After:       p.Cursors.After,
+		graph:       true,
+	}
+}
+
+// graphUser is a user as returned by the Graph API.
+type graphUser struct {
+	ID         string `json:"id"`
+	Username   string `json:"username"`
+	MediaCount *int64 `json:"media_count"`
+}
+
+func (u graphUser) user() *User {
+	user := &User{
+		ID:       u.ID,
+		Username: u.Username,
+	}
+	if u.MediaCount != nil {
+		user.Counts = &UserCounts{Media: *u.MediaCount}
+	}
+	return user
+}
+
+// graphMedia is a media as returned by the Graph API.
+type graphMedia struct {
+	ID           string `json:"id"`
+	Caption      string `json:"caption"`
+	MediaType    string `json:"media_type"`
+	MediaURL     string `json:"media_url"`
+	Permalink    string `json:"permalink"`
+	ThumbnailURL string `json:"thumbnail_url"`
+	Timestamp    string `json:"timestamp"`
+	Username     string `json:"username"`
+	LikeCount    int64  `json:"like_count"`
+	Comments     int64  `json:"comments_count"`
+	Children     *struct {
+		Data []graphMedia `json:"data"`
+	} `json:"children"`
+}
+
+// graphTimeLayout is the format of Graph API timestamps, such as
+// 2017-08-31T18:10:00+0000.
+const graphTimeLayout = "2006-01-02T15:04:05-0700"
+
+// graphMediaTypes maps Graph API media types to Media.Type.
+var graphMediaTypes = map[string]string{
+	"IMAGE":          "image",
+	"VIDEO":          "video",
+	"CAROUSEL_ALBUM": "carousel",
+}
+
+func (m graphMedia) mediaType() string {
+	if t, ok := graphMediaTypes[m.MediaType]; ok {
+		return t
+	}
+	return strings.ToLower(m.MediaType)
+}
+
+// variants returns the images and videos of the media. Videos have their
+// thumbnail as the image, like the API.
+func (m graphMedia) variants() (images MediaVariants, videos MediaVariants) {
+	switch {
+	case m.MediaType == "VIDEO":
+		if m.MediaURL != "" {
+			videos.StandardResolution = &MediaVariant{URL: m.MediaURL}
+		}
+		if m.ThumbnailURL != "" {
+			images.StandardResolution = &MediaVariant{URL: m.ThumbnailURL}
+		}
+	case m.MediaURL != "":
+		images.StandardResolution = &MediaVariant{URL: m.MediaURL}
+	}
+	return
+}
+
+func (m graphMedia) media() (Media, error) {
+	media := Media{
+		ID:   m.ID,
+		Type: m.mediaType(),
+		Link: m.Permalink,
+		User: User{Username: m.Username},
+
+		Likes:    Likes{Count: m.LikeCount},
+		Comments: Comments{Count: m.Comments},
+	}
+	if m.Caption != "" {
+		media.Caption = Comment{Text: m.Caption}
+	}
+	if m.Timestamp != "" {
+		t, err := time.Parse(graphTimeLayout, m.Timestamp)
+		if err != nil {
+			return Media{}, err
+		}
+		media.CreatedTime = t.UTC()
+		media.Caption.CreatedTime = media.CreatedTime
+	}
+	media.Images, media.Videos = m.variants()
+	if m.Children != nil {
+		for _, c := range m.Children.Data {
+			media.CarouselMedias = append(media.CarouselMedias, c.carouselMedia())
+		}
+	}
+	return media, nil
+}
+
+func (m graphMedia) carouselMedia() CarouselMedia {
+	c := CarouselMedia{Type: m.mediaType()}
+	c.Images, c.Videos = m.variants()
+	return c
+}
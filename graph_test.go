@@ -0,0 +1,274 @@
+package instagram
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kr/pretty"
+)
+
+func TestGraphGetMe(t *testing.T) {
+	var query string
+	g, server := newTestGraphServer(t, func(r *http.Request) string {
+		query = r.URL.RawQuery
+		return "graph_me"
+	})
+	defer server.Close()
+
+	res, err := g.GetMe(context.Background())
+	if err != nil {
+		t.Fatalf("GetMe: %s", err)
+	}
+
+	want := &User{
+		ID:       "17841405793187218",
+		Username: "go_ig_test_0219",
+		Counts:   &UserCounts{Media: 4},
+	}
+	if got := res.User; !reflect.DeepEqual(got, want) {
+		t.Errorf("User got %#v want %#v", got, want)
+	}
+	if got, want := query, "access_token=t0ken&fields=id%2Cusername%2Cmedia_count"; got != want {
+		t.Errorf("Query got %s want %s", got, want)
+	}
+	if got, want := res.Response.StatusCode, 200; got != want {
+		t.Errorf("StatusCode got %d want %d", got, want)
+	}
+}
+
+func TestGraphGetMedia(t *testing.T) {
+	g, server := newTestGraphServer(t, func(r *http.Request) string {
+		switch r.URL.Path {
+		case "/17895695668004550":
+			return "graph_media"
+		case "/17895695668004550/children":
+			return "graph_children"
+		}
+		return "graph_not_found"
+	})
+	defer server.Close()
+
+	ctx := context.Background()
+	image := &MediaVariant{URL: "https://scontent.cdninstagram.com/v/t51.2885-15/51287969_2359910680962588_7283258652285168337_n.jpg"}
+	children := []CarouselMedia{
+		{
+			Type: "image",
+			Images: MediaVariants{
+				StandardResolution: image,
+			},
+		},
+		{
+			Type: "video",
+			Images: MediaVariants{
+				StandardResolution: &MediaVariant{URL: "https://scontent.cdninstagram.com/v/t51.2885-15/52133795_250694959199695_5368374984429273088_n.jpg"},
+			},
+			Videos: MediaVariants{
+				StandardResolution: &MediaVariant{URL: "https://video.cdninstagram.com/v/t50.2886-16/52613010_405348530239650_6898777355045568512_n.mp4"},
+			},
+		},
+	}
+	created := time.Date(2019, 2, 14, 19, 41, 20, 0, time.UTC)
+	want := Media{
+		ID:          "17895695668004550",
+		Type:        "carousel",
+		CreatedTime: created,
+		Link:        "https://www.instagram.com/p/Bt39H_1HPI5YVhqU2BTNiI40_wBQerAXmEDoeU0/",
+		User: User{
+			Username: "go_ig_test_0219",
+		},
+		Caption: Comment{
+			Text:        "Carousel photo and video #0219test #carouseltest",
+			CreatedTime: created,
+		},
+		Images: MediaVariants{
+			StandardResolution: image,
+		},
+		CarouselMedias: children,
+	}
+
+	res, err := g.GetMedia(ctx, "17895695668004550")
+	if err != nil {
+		t.Fatalf("GetMedia: %s", err)
+	}
+	if got := *res.Media; !reflect.DeepEqual(got, want) {
+		t.Errorf("Media not equal\nhave: %#v\nwant: %#v", got, want)
+		pretty.Ldiff(t, got, want)
+	}
+
+	cres, err := g.GetMediaChildren(ctx, "17895695668004550")
+	if err != nil {
+		t.Fatalf("GetMediaChildren: %s", err)
+	}
+	if got := cres.CarouselMedias; !reflect.DeepEqual(got, children) {
+		t.Errorf("Children not equal\nhave: %#v\nwant: %#v", got, children)
+		pretty.Ldiff(t, got, children)
+	}
+
+	_, err = g.GetMedia(ctx, "123")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Want ErrNotFound got %v", err)
+	}
+	var gerr *GraphError
+	if !errors.As(err, &gerr) {
+		t.Fatalf("Want GraphError got %T", err)
+	}
+	if got, want := gerr.FBTraceID, "AvWbr8hqlm9zPbSGuQmOkbn"; got != want {
+		t.Errorf("FBTraceID got %s want %s", got, want)
+	}
+}
+
+func TestGraphGetMyMedia(t *testing.T) {
+	g, server := newTestGraphServer(t, func(r *http.Request) string {
+		if r.URL.Path != "/me/media" {
+			return ""
+		}
+		return "graph_media_list"
+	})
+	defer server.Close()
+
+	res, err := g.GetMyMedia(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("GetMyMedia: %s", err)
+	}
+	if got, want := len(res.Medias), 2; got != want {
+		t.Fatalf("Medias got %d want %d", got, want)
+	}
+
+	video := res.Medias[1]
+	if got, want := video.Type, "video"; got != want {
+		t.Errorf("Type got %s want %s", got, want)
+	}
+	if got, want := video.Videos.StandardResolution.URL, "https://video.cdninstagram.com/v/t50.2886-16/52696644_485271351879016_7856051086296088576_n.mp4"; got != want {
+		t.Errorf("Video URL got %s want %s", got, want)
+	}
+	if got, want := video.Images.StandardResolution.URL, "https://scontent.cdninstagram.com/v/t51.2885-15/50863106_2133926990002507_2324727490466410627_n.jpg"; got != want {
+		t.Errorf("Thumbnail URL got %s want %s", got, want)
+	}
+	if got, want := video.CreatedTime, time.Date(2019, 2, 14, 19, 39, 20, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("CreatedTime got %s want %s", got, want)
+	}
+}
+
+func TestGraphIterateMedia(t *testing.T) {
+	var queries []url.Values
+	g, server := newTestGraphServer(t, func(r *http.Request) string {
+		queries = append(queries, r.URL.Query())
+		q := r.URL.Query()
+		switch {
+		case r.URL.Path == "/me/media" && q.Get("before") == "":
+			return "graph_media_list"
+		case r.URL.Path == "/me/media":
+			return "graph_media_list_last"
+		case r.URL.Path == "/v12.0/17841405793187218/media" && q.Get("after") != "":
+			return "graph_media_list_last"
+		case r.URL.Path == "/v12.0/17841405793187218/media" && q.Get("before") != "":
+			return "graph_media_list"
+		}
+		return "graph_not_found"
+	})
+	defer server.Close()
+
+	tests := []struct {
+		desc   string
+		params url.Values
+		dir    Direction
+		want   []string
+	}{
+		{
+			desc: "forward",
+			dir:  Forward,
+			want: []string{"17918920912032398", "17844368185388443", "17895695668004550"},
+		},
+		{
+			desc:   "backward",
+			params: PageParams{Before: "QVFIUmJsZAV3"}.Values(),
+			dir:    Backward,
+			want:   []string{"17895695668004550", "17844368185388443", "17918920912032398"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			queries = nil
+			ctx := context.Background()
+			res, err := g.GetMyMedia(ctx, tt.params)
+			if err != nil {
+				t.Fatalf("GetMyMedia: %s", err)
+			}
+			var got []string
+			mediaChan, errChan := g.IterateMedia(ctx, res, tt.dir)
+			for m := range mediaChan {
+				got = append(got, m.ID)
+			}
+			if err := <-errChan; err != nil {
+				t.Fatalf("IterateMedia: %s", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("IDs got %v want %v", got, tt.want)
+			}
+			for _, q := range queries {
+				if got, want := q.Get("access_token"), "t0ken"; got != want {
+					t.Errorf("access_token got %s want %s", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestGraphRequiresAccessToken(t *testing.T) {
+	g := NewGraph(&API{ClientID: "c1", HTTPClient: &http.Client{}})
+	if _, err := g.GetMe(context.Background()); err == nil {
+		t.Errorf("Want error without access token")
+	}
+}
+
+func TestGraphErrorIs(t *testing.T) {
+	tests := []struct {
+		desc          string
+		err           *GraphError
+		want          error
+		wantRetryable bool
+	}{
+		{
+			desc: "invalid token",
+			err:  &GraphError{StatusCode: 400, Type: "OAuthException", Code: 190},
+			want: ErrInvalidToken,
+		},
+		{
+			desc:          "rate limit",
+			err:           &GraphError{StatusCode: 400, Code: 4},
+			want:          ErrRateLimited,
+			wantRetryable: true,
+		},
+		{
+			desc: "permission",
+			err:  &GraphError{StatusCode: 400, Code: 10},
+			want: ErrPermissionDenied,
+		},
+		{
+			desc:          "transient",
+			err:           &GraphError{StatusCode: 500, Code: 2, IsTransient: true},
+			wantRetryable: true,
+		},
+		{
+			desc: "invalid parameter",
+			err:  &GraphError{StatusCode: 400, Code: 100},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			for _, s := range []error{ErrInvalidToken, ErrRateLimited, ErrNotFound, ErrPermissionDenied} {
+				if got, want := errors.Is(tt.err, s), s == tt.want; got != want {
+					t.Errorf("errors.Is(%v) got %t want %t", s, got, want)
+				}
+			}
+			if got, want := tt.err.Retryable(), tt.wantRetryable; got != want {
+				t.Errorf("Retryable got %t want %t", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package instagram
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// SearchHashtag returns the ID of a hashtag, to get its media. Searches
+// count against the limit of 30 unique hashtags per 7 days of userID, a
+// business or creator account, which Graph.HashtagBudget enforces if set.
+// Graph API: GET /ig_hashtag_search
+func (g *Graph) SearchHashtag(ctx context.Context, userID string, name string) (res *HashtagResponse, err error) {
+	res = new(HashtagResponse)
+	tag := NormalizeTag(name)
+	if tag == "" {
+		err = errors.New("instagram: tag name is empty")
+		return
+	}
+	if g.HashtagBudget != nil {
+		if err = g.HashtagBudget.spend(userID, tag, time.Now()); err != nil {
+			return
+		}
+	}
+	params := url.Values{}
+	params.Set("user_id", userID)
+	params.Set("q", tag)
+	err = g.get(ctx, "SearchHashtag", "/ig_hashtag_search", params, res)
+	if res.Hashtag != nil {
+		res.Hashtag.Name = tag
+	}
+	return
+}
+
+// GetHashtagTopMedia returns the most popular media of a hashtag, with
+// fields or DefaultHashtagMediaFields.
+// Graph API: GET /{ig-hashtag-id}/top_media
+func (g *Graph) GetHashtagTopMedia(ctx context.Context, hashtagID string, userID string, params url.Values, fields ...MediaField) (res *GraphMediasResponse, err error) {
+	return g.hashtagMedia(ctx, "GetHashtagTopMedia", "top_media", hashtagID, userID, params, fields)
+}
+
+// GetHashtagRecentMedia returns the media of a hashtag from the last 24
+// hours, with fields or DefaultHashtagMediaFields.
+// Graph API: GET /{ig-hashtag-id}/recent_media
+func (g *Graph) GetHashtagRecentMedia(ctx context.Context, hashtagID string, userID string, params url.Values, fields ...MediaField) (res *GraphMediasResponse, err error) {
+	return g.hashtagMedia(ctx, "GetHashtagRecentMedia", "recent_media", hashtagID, userID, params, fields)
+}
+
+func (g *Graph) hashtagMedia(ctx context.Context, endpoint string, edge string, hashtagID string, userID string, params url.Values, fields []MediaField) (res *GraphMediasResponse, err error) {
+	res = new(GraphMediasResponse)
+	params = copyParams(params)
+	params.Set("user_id", userID)
+	params.Set("fields", mediaFieldsParam(fields, defaultHashtagMediaFields))
+	err = g.get(ctx, endpoint, fmt.Sprintf("/%s/%s", url.PathEscape(hashtagID), edge), params, res)
+	return
+}
+
+// HashtagResponse is the Graph API response for SearchHashtag()
+type HashtagResponse struct {
+	graphResponse
+
+	// Hashtag is nil if there is no such hashtag.
+	Hashtag *Hashtag
+}
+
+// Hashtag is a hashtag of the Graph API.
+type Hashtag struct {
+	ID   string
+	Name string
+}
+
+// UnmarshalJSON implements JSON.
+func (r *HashtagResponse) UnmarshalJSON(in []byte) error {
+	var page struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(in, &page); err != nil {
+		return err
+	}
+	if len(page.Data) > 0 {
+		r.Hashtag = &Hashtag{ID: page.Data[0].ID}
+	}
+	return nil
+}
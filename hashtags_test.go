@@ -0,0 +1,119 @@
+package instagram
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestSearchHashtag(t *testing.T) {
+	var queries []url.Values
+	g, server := newTestGraphServer(t, func(r *http.Request) string {
+		queries = append(queries, r.URL.Query())
+		if r.URL.Path != "/ig_hashtag_search" {
+			return ""
+		}
+		if r.URL.Query().Get("q") == "nosuchtag" {
+			return "graph_hashtag_search_empty"
+		}
+		return "graph_hashtag_search"
+	})
+	defer server.Close()
+	g.HashtagBudget = &HashtagBudget{Store: NewMemoryHashtagStore(), Limit: 2}
+
+	ctx := context.Background()
+	res, err := g.SearchHashtag(ctx, "17841405793187218", " #Coke")
+	if err != nil {
+		t.Fatalf("SearchHashtag: %s", err)
+	}
+	if got, want := res.Hashtag, (&Hashtag{ID: "17843857450040591", Name: "coke"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("Hashtag got %v want %v", got, want)
+	}
+	wantQuery := url.Values{
+		"access_token": {"t0ken"},
+		"user_id":      {"17841405793187218"},
+		"q":            {"coke"},
+	}
+	if got := queries[0]; !reflect.DeepEqual(got, wantQuery) {
+		t.Errorf("Query got %v want %v", got, wantQuery)
+	}
+
+	res, err = g.SearchHashtag(ctx, "17841405793187218", "nosuchtag")
+	if err != nil {
+		t.Fatalf("SearchHashtag: %s", err)
+	}
+	if res.Hashtag != nil {
+		t.Errorf("Hashtag got %v want nil", res.Hashtag)
+	}
+
+	// The budget is spent, so the request isn't made.
+	res, err = g.SearchHashtag(ctx, "17841405793187218", "pepsi")
+	if !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Error got %v want %v", err, ErrRateLimited)
+	}
+	if res == nil {
+		t.Errorf("Response got nil with budget error")
+	}
+	if res, err := g.SearchHashtag(ctx, "17841405793187218", " # "); err == nil || res == nil {
+		t.Errorf("Empty tag got %v, %v want response and error", res, err)
+	}
+	if got, want := len(queries), 2; got != want {
+		t.Errorf("Requests got %d want %d", got, want)
+	}
+
+	// Searching a spent hashtag again is free.
+	if _, err := g.SearchHashtag(ctx, "17841405793187218", "coke"); err != nil {
+		t.Errorf("SearchHashtag: %s", err)
+	}
+}
+
+func TestGetHashtagMedia(t *testing.T) {
+	var query url.Values
+	g, server := newTestGraphServer(t, func(r *http.Request) string {
+		query = r.URL.Query()
+		switch r.URL.Path {
+		case "/17843857450040591/top_media", "/17843857450040591/recent_media":
+			return "graph_hashtag_media"
+		}
+		return ""
+	})
+	defer server.Close()
+
+	tests := []struct {
+		desc string
+		get  func(context.Context, string, string, url.Values, ...MediaField) (*GraphMediasResponse, error)
+	}{
+		{desc: "top", get: g.GetHashtagTopMedia},
+		{desc: "recent", get: g.GetHashtagRecentMedia},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			res, err := tt.get(context.Background(), "17843857450040591", "17841405793187218", PageParams{Limit: 50}.Values())
+			if err != nil {
+				t.Fatalf("get: %s", err)
+			}
+			if got, want := query.Get("user_id"), "17841405793187218"; got != want {
+				t.Errorf("user_id got %s want %s", got, want)
+			}
+			if got, want := query.Get("limit"), "50"; got != want {
+				t.Errorf("limit got %s want %s", got, want)
+			}
+			if got, want := query.Get("fields"), DefaultHashtagMediaFields().String(); got != want {
+				t.Errorf("fields got %s want %s", got, want)
+			}
+			if got, want := len(res.Medias), 1; got != want {
+				t.Fatalf("Medias got %d want %d", got, want)
+			}
+			m := res.Medias[0]
+			if m.Likes.Count != 40 || m.Comments.Count != 3 {
+				t.Errorf("Counts got %d likes %d comments want 40 and 3", m.Likes.Count, m.Comments.Count)
+			}
+			if got, want := res.Pagination.After, "NTAyYzQ2"; got != want {
+				t.Errorf("After got %s want %s", got, want)
+			}
+		})
+	}
+}
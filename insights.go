@@ -0,0 +1,199 @@
+package instagram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Metric is an insight metric. Insights are available for business and
+// creator accounts only.
+type Metric string
+
+// Metrics of users, for GetUserInsights.
+const (
+	MetricImpressions   Metric = "impressions"
+	MetricReach         Metric = "reach"
+	MetricProfileViews  Metric = "profile_views"
+	MetricFollowerCount Metric = "follower_count"
+	MetricWebsiteClicks Metric = "website_clicks"
+	MetricEmailContacts Metric = "email_contacts"
+	MetricAudienceCity  Metric = "audience_city"
+)
+
+// Metrics of media, for GetMediaInsights. MetricImpressions and MetricReach
+// apply to media too.
+const (
+	MetricEngagement Metric = "engagement"
+	MetricSaved      Metric = "saved"
+	MetricVideoViews Metric = "video_views"
+)
+
+// Period is the period an insight value is aggregated over.
+type Period string
+
+// Periods of insights.
+const (
+	PeriodDay      Period = "day"
+	PeriodWeek     Period = "week"
+	PeriodDays28   Period = "days_28"
+	PeriodLifetime Period = "lifetime"
+)
+
+// InsightsParams are the params of GetUserInsights.
+type InsightsParams struct {
+	Metrics []Metric
+	Period  Period
+
+	// Since and Until limit the time range. Zero values are left out.
+	Since time.Time
+	Until time.Time
+}
+
+func (p InsightsParams) values() (url.Values, error) {
+	if len(p.Metrics) == 0 {
+		return nil, errors.New("instagram: no insight metrics")
+	}
+	params := PageParams{Since: p.Since, Until: p.Until}.Values()
+	params.Set("metric", metricsParam(p.Metrics))
+	if p.Period != "" {
+		params.Set("period", string(p.Period))
+	}
+	return params, nil
+}
+
+func metricsParam(metrics []Metric) string {
+	names := make([]string, len(metrics))
+	for i, m := range metrics {
+		names[i] = string(m)
+	}
+	return strings.Join(names, ",")
+}
+
+// GetUserInsights returns insights of a business or creator account over
+// time. Follow the Pagination to get earlier or later time ranges.
+// Graph API: GET /{ig-user-id}/insights
+func (g *Graph) GetUserInsights(ctx context.Context, userID string, p InsightsParams) (res *InsightsResponse, err error) {
+	res = new(InsightsResponse)
+	params, err := p.values()
+	if err != nil {
+		return
+	}
+	err = g.get(ctx, "GetUserInsights", fmt.Sprintf("/%s/insights", url.PathEscape(userID)), params, res)
+	return
+}
+
+// GetMediaInsights returns the lifetime insights of a media.
+// Graph API: GET /{ig-media-id}/insights
+func (g *Graph) GetMediaInsights(ctx context.Context, mediaID string, metrics ...Metric) (res *InsightsResponse, err error) {
+	res = new(InsightsResponse)
+	if len(metrics) == 0 {
+		err = errors.New("instagram: no insight metrics")
+		return
+	}
+	params := url.Values{}
+	params.Set("metric", metricsParam(metrics))
+	err = g.get(ctx, "GetMediaInsights", fmt.Sprintf("/%s/insights", url.PathEscape(mediaID)), params, res)
+	return
+}
+
+// NextInsights returns the insights of the next time range
+func (g *Graph) NextInsights(ctx context.Context, ip InsightPagination) (res *InsightsResponse, err error) {
+	res = new(InsightsResponse)
+	err = g.page(ctx, "NextInsights", ip.Pagination, Forward, res)
+	return
+}
+
+// PreviousInsights returns the insights of the previous time range
+func (g *Graph) PreviousInsights(ctx context.Context, ip InsightPagination) (res *InsightsResponse, err error) {
+	res = new(InsightsResponse)
+	err = g.page(ctx, "PreviousInsights", ip.Pagination, Backward, res)
+	return
+}
+
+// InsightsResponse is the Graph API response for GetUserInsights() and
+// GetMediaInsights()
+type InsightsResponse struct {
+	graphResponse
+	Insights   []Insight
+	Pagination InsightPagination
+}
+
+func (r *InsightsResponse) pagination() *Pagination {
+	return &r.Pagination.Pagination
+}
+
+// InsightPagination will give you an easy way to request the insights of the
+// previous or next time range.
+type InsightPagination struct {
+	Pagination
+}
+
+// UnmarshalJSON implements JSON.
+func (r *InsightsResponse) UnmarshalJSON(in []byte) error {
+	var page struct {
+		Data   []Insight   `json:"data"`
+		Paging graphPaging `json:"paging"`
+	}
+	if err := json.Unmarshal(in, &page); err != nil {
+		return err
+	}
+	r.Insights = page.Data
+	r.Pagination = InsightPagination{page.Paging.pagination()}
+	return nil
+}
+
+// Insight is a time series of a metric.
+type Insight struct {
+	ID          string         `json:"id"`
+	Name        Metric         `json:"name"`
+	Period      Period         `json:"period"`
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	Values      []InsightValue `json:"values"`
+}
+
+// InsightValue is the value of a metric for the period that ends at EndTime.
+// Lifetime values have no EndTime.
+type InsightValue struct {
+	Value   int64
+	EndTime time.Time
+
+	// Breakdown is set instead of Value by metrics broken down by key,
+	// such as MetricAudienceCity.
+	Breakdown map[string]int64
+}
+
+// UnmarshalJSON implements JSON.
+func (v *InsightValue) UnmarshalJSON(in []byte) error {
+	var raw struct {
+		Value   json.RawMessage `json:"value"`
+		EndTime string          `json:"end_time"`
+	}
+	if err := json.Unmarshal(in, &raw); err != nil {
+		return err
+	}
+	*v = InsightValue{}
+	if bytes.HasPrefix(bytes.TrimSpace(raw.Value), []byte("{")) {
+		if err := json.Unmarshal(raw.Value, &v.Breakdown); err != nil {
+			return err
+		}
+	} else if len(raw.Value) > 0 {
+		if err := json.Unmarshal(raw.Value, &v.Value); err != nil {
+			return err
+		}
+	}
+	if raw.EndTime != "" {
+		t, err := time.Parse(graphTimeLayout, raw.EndTime)
+		if err != nil {
+			return err
+		}
+		v.EndTime = t.UTC()
+	}
+	return nil
+}
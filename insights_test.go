@@ -0,0 +1,148 @@
+package instagram
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kr/pretty"
+)
+
+func TestGetUserInsights(t *testing.T) {
+	var queries []url.Values
+	g, server := newTestGraphServer(t, func(r *http.Request) string {
+		queries = append(queries, r.URL.Query())
+		switch r.URL.Path {
+		case "/17841405793187218/insights", "/v12.0/17841405793187218/insights":
+			return "graph_user_insights"
+		}
+		return "graph_not_found"
+	})
+	defer server.Close()
+
+	ctx := context.Background()
+	res, err := g.GetUserInsights(ctx, "17841405793187218", InsightsParams{
+		Metrics: []Metric{MetricImpressions, MetricReach},
+		Period:  PeriodDay,
+		Since:   time.Date(2019, 2, 12, 0, 0, 0, 0, time.UTC),
+		Until:   time.Date(2019, 2, 14, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("GetUserInsights: %s", err)
+	}
+	wantQuery := url.Values{
+		"access_token": {"t0ken"},
+		"metric":       {"impressions,reach"},
+		"period":       {"day"},
+		"since":        {"1549929600"},
+		"until":        {"1550102400"},
+	}
+	if got := queries[0]; !reflect.DeepEqual(got, wantQuery) {
+		t.Errorf("Query got %v want %v", got, wantQuery)
+	}
+
+	if got, want := len(res.Insights), 2; got != want {
+		t.Fatalf("Insights got %d want %d", got, want)
+	}
+	want := Insight{
+		ID:          "17841405793187218/insights/reach/day",
+		Name:        MetricReach,
+		Period:      PeriodDay,
+		Title:       "Reach",
+		Description: "Total number of times the Business Account's media objects have been uniquely viewed",
+		Values: []InsightValue{
+			{Value: 12, EndTime: time.Date(2019, 2, 13, 8, 0, 0, 0, time.UTC)},
+			{Value: 15, EndTime: time.Date(2019, 2, 14, 8, 0, 0, 0, time.UTC)},
+		},
+	}
+	if got := res.Insights[1]; !reflect.DeepEqual(got, want) {
+		t.Errorf("Insight diff %s", pretty.Diff(got, want))
+	}
+
+	if _, err := g.NextInsights(ctx, res.Pagination); err != nil {
+		t.Fatalf("NextInsights: %s", err)
+	}
+	if _, err := g.PreviousInsights(ctx, res.Pagination); err != nil {
+		t.Fatalf("PreviousInsights: %s", err)
+	}
+	if got, want := queries[1].Get("since"), "1550188800"; got != want {
+		t.Errorf("Next since got %s want %s", got, want)
+	}
+	if got, want := queries[2].Get("until"), "1550016000"; got != want {
+		t.Errorf("Previous until got %s want %s", got, want)
+	}
+}
+
+func TestGetMediaInsights(t *testing.T) {
+	tests := []struct {
+		desc    string
+		fixture string
+		metrics []Metric
+		want    []InsightValue
+		wantErr error
+	}{
+		{
+			desc:    "lifetime",
+			fixture: "graph_media_insights",
+			metrics: []Metric{MetricSaved},
+			want:    []InsightValue{{Value: 3}},
+		},
+		{
+			desc:    "breakdown",
+			fixture: "graph_audience_city",
+			metrics: []Metric{MetricAudienceCity},
+			want: []InsightValue{{
+				EndTime:   time.Date(2019, 2, 14, 8, 0, 0, 0, time.UTC),
+				Breakdown: map[string]int64{"London, England": 5, "Sydney, New South Wales": 2},
+			}},
+		},
+		{
+			desc:    "not enough viewers",
+			fixture: "graph_insights_error",
+			metrics: []Metric{MetricReach},
+			wantErr: ErrPermissionDenied,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			var metric string
+			g, server := newTestGraphServer(t, func(r *http.Request) string {
+				metric = r.URL.Query().Get("metric")
+				return tt.fixture
+			})
+			defer server.Close()
+
+			res, err := g.GetMediaInsights(context.Background(), "17918920912032398", tt.metrics...)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Error got %v want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetMediaInsights: %s", err)
+			}
+			if got, want := metric, string(tt.metrics[0]); got != want {
+				t.Errorf("metric got %s want %s", got, want)
+			}
+			if got := res.Insights[0].Values; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Values diff %s", pretty.Diff(got, tt.want))
+			}
+		})
+	}
+}
+
+func TestInsightsRequireMetrics(t *testing.T) {
+	g := NewGraph(&API{AccessToken: "t0ken", HTTPClient: &http.Client{}})
+	ctx := context.Background()
+	if res, err := g.GetUserInsights(ctx, "1", InsightsParams{Period: PeriodDay}); err == nil || res == nil {
+		t.Errorf("GetUserInsights got %v, %v want response and error without metrics", res, err)
+	}
+	if res, err := g.GetMediaInsights(ctx, "1"); err == nil || res == nil {
+		t.Errorf("GetMediaInsights got %v, %v want response and error without metrics", res, err)
+	}
+}
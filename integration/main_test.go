@@ -65,6 +65,38 @@ func TestContextDeadline(t *testing.T) {
 	}
 }
 
+func TestGetUser(t *testing.T) {
+	ctx := context.Background()
+	api := newAPI()
+
+	self, err := api.GetSelf(ctx)
+	checkRes(t, self.Meta, err)
+
+	user, err := api.GetUser(ctx, self.User.ID)
+	checkRes(t, user.Meta, err)
+
+	if user.User.Username != self.User.Username {
+		t.Errorf("Username got %s want %s", user.User.Username, self.User.Username)
+	}
+}
+
+func TestGetUserRecentMedia(t *testing.T) {
+	ctx := context.Background()
+	api := newAPI()
+
+	self, err := api.GetSelf(ctx)
+	checkRes(t, self.Meta, err)
+
+	params := url.Values{}
+	params.Set("count", "1")
+	res, err := api.GetUserRecentMedia(ctx, self.User.ID, params)
+	checkRes(t, res.Meta, err)
+
+	if len(res.Medias) != 1 {
+		t.Error("Count didn't apply")
+	}
+}
+
 func TestGetRecentMedia(t *testing.T) {
 	ctx := context.Background()
 	api := newAPI()
@@ -149,7 +181,7 @@ func TestGetMediaRecentComments(t *testing.T) {
 		t.Fatalf("get media id: %s", err)
 	}
 
-	res, err := api.GetMediaRecentComments(ctx, mediaID)
+	res, err := api.GetMediaRecentComments(ctx, mediaID, nil)
 	checkRes(t, res.Meta, err)
 
 	if len(res.Comments) == 0 {
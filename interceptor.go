@@ -0,0 +1,54 @@
+package instagram
+
+import (
+	"context"
+	"net/url"
+)
+
+// Request is a logical API request, as seen by an Interceptor. Params do not
+// include the credentials or signature, which are added when the request is
+// sent.
+type Request struct {
+	// Endpoint is the name of the API method, for instance "GetSelf".
+	// Graph methods are prefixed with "Graph.", for instance
+	// "Graph.GetMe".
+	Endpoint string
+	Method   string
+	Path     string
+	Params   url.Values
+
+	// Result is the response the body is decoded into, for instance a
+	// *UserResponse.
+	Result interface{}
+
+	// graph is set for requests to the Graph API.
+	graph bool
+
+	// noCache is set for requests whose responses must not be cached,
+	// such as polls of a status.
+	noCache bool
+}
+
+// Handler executes a Request, decoding the response into req.Result.
+type Handler func(ctx context.Context, req *Request) error
+
+// Interceptor wraps the execution of a Request. It may inspect or change req
+// before calling next, and inspect req.Result or the returned error after.
+// It may also return without calling next, for instance to serve a result
+// from a cache.
+type Interceptor func(ctx context.Context, req *Request, next Handler) error
+
+// execute runs req through the interceptors and sends it.
+func (api *API) execute(ctx context.Context, req *Request) error {
+	h := api.send
+	for i := len(api.Interceptors) - 1; i >= 0; i-- {
+		h = intercept(api.Interceptors[i], h)
+	}
+	return h(ctx, req)
+}
+
+func intercept(ic Interceptor, next Handler) Handler {
+	return func(ctx context.Context, req *Request) error {
+		return ic(ctx, req, next)
+	}
+}
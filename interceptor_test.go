@@ -0,0 +1,111 @@
+package instagram
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestInterceptors(t *testing.T) {
+	api, server := newTestAPIServer(t, func(r *http.Request) string {
+		if r.URL.Path == "/v1/users/self" {
+			return "invalid_token"
+		}
+		return "image"
+	})
+	defer server.Close()
+	api.AccessToken = "secret"
+
+	var calls []string
+	record := func(name string) Interceptor {
+		return func(ctx context.Context, req *Request, next Handler) error {
+			calls = append(calls, name+" "+req.Endpoint)
+			err := next(ctx, req)
+			calls = append(calls, name+" done")
+			return err
+		}
+	}
+
+	var seen []*Request
+	var seenErr error
+	inspect := func(ctx context.Context, req *Request, next Handler) error {
+		seen = append(seen, req)
+		seenErr = next(ctx, req)
+		return seenErr
+	}
+
+	api.Interceptors = []Interceptor{record("outer"), record("inner"), inspect}
+
+	ctx := context.Background()
+	params := url.Values{"count": {"1"}}
+	res, err := api.GetRecentMedia(ctx, params)
+	if err != nil {
+		t.Fatalf("GetRecentMedia: %s", err)
+	}
+
+	wantCalls := []string{
+		"outer GetRecentMedia",
+		"inner GetRecentMedia",
+		"inner done",
+		"outer done",
+	}
+	if !reflect.DeepEqual(calls, wantCalls) {
+		t.Errorf("Calls got %v want %v", calls, wantCalls)
+	}
+
+	req := seen[0]
+	if got, want := req.Path, "/users/self/media/recent"; got != want {
+		t.Errorf("Path got %s want %s", got, want)
+	}
+	if got, want := req.Params, params; !reflect.DeepEqual(got, want) {
+		t.Errorf("Params got %v want %v", got, want)
+	}
+	if req.Result != res {
+		t.Errorf("Result is not the response")
+	}
+	if got, want := params.Get("access_token"), ""; got != want {
+		t.Errorf("Caller params were modified")
+	}
+
+	if _, err := api.NextMedias(ctx, res.Pagination); err != nil {
+		t.Fatalf("NextMedias: %s", err)
+	}
+	if got, want := seen[1].Endpoint, "NextMedias"; got != want {
+		t.Errorf("Endpoint got %s want %s", got, want)
+	}
+	if _, ok := seen[1].Params["access_token"]; ok {
+		t.Errorf("Interceptor saw the access token")
+	}
+
+	_, err = api.GetSelf(ctx)
+	if !errors.Is(seenErr, ErrInvalidToken) || seenErr != err {
+		t.Errorf("Interceptor error got %v want %v", seenErr, err)
+	}
+}
+
+func TestInterceptorShortCircuit(t *testing.T) {
+	api, server := newTestAPIServer(t, func(r *http.Request) string {
+		t.Errorf("Unexpected request %s", r.URL)
+		return "image"
+	})
+	defer server.Close()
+
+	api.Interceptors = []Interceptor{
+		func(ctx context.Context, req *Request, next Handler) error {
+			res := req.Result.(*UserResponse)
+			res.User = &User{ID: "1"}
+			return nil
+		},
+	}
+
+	res, err := api.GetSelf(context.Background())
+	if err != nil {
+		t.Fatalf("GetSelf: %s", err)
+	}
+	if got, want := res.User.ID, "1"; got != want {
+		t.Errorf("User ID got %s want %s", got, want)
+	}
+}
@@ -45,3 +45,132 @@ func (api *API) IterateMedia(ctx context.Context, res *PaginatedMediasResponse)
 
 	return mediaChan, errChan
 }
+
+// IterateComments makes pagination easy by converting the repeated
+// api.NextComments() call to a channel of comments. Comments are passed in
+// the order of each page. Use context to cancel iteration.
+func (api *API) IterateComments(ctx context.Context, res *CommentsResponse) (<-chan *Comment, <-chan error) {
+	commentChan := make(chan *Comment)
+	errChan := make(chan error, 1)
+
+	go func() {
+		defer close(commentChan)
+		defer close(errChan)
+
+		for {
+			if res == nil {
+				return
+			}
+			if len(res.Comments) == 0 {
+				return
+			}
+
+			for i := range res.Comments {
+				select {
+				case <-ctx.Done():
+					return
+				case commentChan <- &res.Comments[i]:
+				}
+			}
+
+			// Paginate to next response
+			var err error
+			res, err = api.NextComments(ctx, res.Pagination)
+			if err != nil {
+				errChan <- err
+				return
+			}
+		}
+	}()
+
+	return commentChan, errChan
+}
+
+// IterateUsers makes pagination easy by converting the repeated
+// api.NextUsers() call to a channel of users. Users are passed in the order
+// of each page. Use context to cancel iteration.
+func (api *API) IterateUsers(ctx context.Context, res *UsersResponse) (<-chan *User, <-chan error) {
+	userChan := make(chan *User)
+	errChan := make(chan error, 1)
+
+	go func() {
+		defer close(userChan)
+		defer close(errChan)
+
+		for {
+			if res == nil {
+				return
+			}
+			if len(res.Users) == 0 {
+				return
+			}
+
+			for i := range res.Users {
+				select {
+				case <-ctx.Done():
+					return
+				case userChan <- &res.Users[i]:
+				}
+			}
+
+			// Paginate to next response
+			var err error
+			res, err = api.NextUsers(ctx, res.Pagination)
+			if err != nil {
+				errChan <- err
+				return
+			}
+		}
+	}()
+
+	return userChan, errChan
+}
+
+// IterateMedia makes pagination easy by converting the repeated
+// g.NextMedias() or g.PreviousMedias() call to a channel of media. Forward
+// passes media in the order of each page, Backward in reverse order, so
+// either direction passes media in a consistent order. Use context to
+// cancel iteration.
+func (g *Graph) IterateMedia(ctx context.Context, res *GraphMediasResponse, dir Direction) (<-chan *Media, <-chan error) {
+	mediaChan := make(chan *Media)
+	errChan := make(chan error, 1)
+
+	go func() {
+		defer close(mediaChan)
+		defer close(errChan)
+
+		for {
+			if res == nil {
+				return
+			}
+			if len(res.Medias) == 0 {
+				return
+			}
+
+			for i := range res.Medias {
+				if dir == Backward {
+					i = len(res.Medias) - 1 - i
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case mediaChan <- &res.Medias[i]:
+				}
+			}
+
+			// Paginate to next response
+			var err error
+			if dir == Backward {
+				res, err = g.PreviousMedias(ctx, res.Pagination)
+			} else {
+				res, err = g.NextMedias(ctx, res.Pagination)
+			}
+			if err != nil {
+				errChan <- err
+				return
+			}
+		}
+	}()
+
+	return mediaChan, errChan
+}
@@ -0,0 +1,53 @@
+package instagram
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// GetLocation returns information about a location.
+// REST API: GET /locations/{location-id}
+func (api *API) GetLocation(ctx context.Context, locationID string) (res *LocationResponse, err error) {
+	res = new(LocationResponse)
+	err = api.get(ctx, "GetLocation", fmt.Sprintf("/locations/%s", url.PathEscape(locationID)), nil, res)
+	return
+}
+
+// GetLocationRecentMedia returns the most recent media at a location.
+// REST API: GET /locations/{location-id}/media/recent
+func (api *API) GetLocationRecentMedia(ctx context.Context, locationID string, params url.Values) (res *PaginatedMediasResponse, err error) {
+	res = new(PaginatedMediasResponse)
+	err = api.get(ctx, "GetLocationRecentMedia", fmt.Sprintf("/locations/%s/media/recent", url.PathEscape(locationID)), params, res)
+	return
+}
+
+// SearchLocations returns locations within distance meters of a point. A
+// distance of zero uses the API's default of 500 meters; the maximum is 750.
+// REST API: GET /locations/search
+func (api *API) SearchLocations(ctx context.Context, lat, lng float64, distance int) (res *LocationsResponse, err error) {
+	res = new(LocationsResponse)
+	err = api.get(ctx, "SearchLocations", "/locations/search", geoParams(lat, lng, distance), res)
+	return
+}
+
+// SearchMedia returns recent media within distance meters of a point. A
+// distance of zero uses the API's default of 1000 meters; the maximum is
+// 5000.
+// REST API: GET /media/search
+func (api *API) SearchMedia(ctx context.Context, lat, lng float64, distance int) (res *PaginatedMediasResponse, err error) {
+	res = new(PaginatedMediasResponse)
+	err = api.get(ctx, "SearchMedia", "/media/search", geoParams(lat, lng, distance), res)
+	return
+}
+
+func geoParams(lat, lng float64, distance int) url.Values {
+	params := url.Values{}
+	params.Set("lat", strconv.FormatFloat(lat, 'f', -1, 64))
+	params.Set("lng", strconv.FormatFloat(lng, 'f', -1, 64))
+	if distance > 0 {
+		params.Set("distance", strconv.Itoa(distance))
+	}
+	return params
+}
@@ -0,0 +1,87 @@
+package instagram
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestLocations(t *testing.T) {
+	var requests []string
+	api, server := newTestAPIServer(t, func(r *http.Request) string {
+		q := r.URL.Query()
+		requests = append(requests, r.URL.Path+" lat="+q.Get("lat")+" lng="+q.Get("lng")+" distance="+q.Get("distance"))
+		switch r.URL.Path {
+		case "/v1/locations/213051194":
+			return "location"
+		case "/v1/locations/213051194/media/recent":
+			return "video"
+		case "/v1/locations/search":
+			return "locations_search"
+		case "/v1/media/search":
+			return "image"
+		}
+		return ""
+	})
+	defer server.Close()
+
+	ctx := context.Background()
+	oakland := Location{
+		ID:        "213051194",
+		Name:      "Oakland, California",
+		Latitude:  37.8029,
+		Longitude: -122.2721,
+	}
+
+	loc, err := api.GetLocation(ctx, "213051194")
+	if err != nil {
+		t.Fatalf("GetLocation: %s", err)
+	}
+	if got, want := *loc.Location, oakland; got != want {
+		t.Errorf("Location got %+v want %+v", got, want)
+	}
+
+	media, err := api.GetLocationRecentMedia(ctx, "213051194", nil)
+	if err != nil {
+		t.Fatalf("GetLocationRecentMedia: %s", err)
+	}
+	if got, want := len(media.Medias), 1; got != want {
+		t.Errorf("Medias got %d want %d", got, want)
+	}
+
+	search, err := api.SearchLocations(ctx, 37.8029, -122.2721, 750)
+	if err != nil {
+		t.Fatalf("SearchLocations: %s", err)
+	}
+	wantLocations := []Location{
+		oakland,
+		{
+			ID:        "1026545497",
+			Name:      "Lake Merritt",
+			Latitude:  37.8019,
+			Longitude: -122.2587,
+		},
+	}
+	if got, want := search.Locations, wantLocations; !reflect.DeepEqual(got, want) {
+		t.Errorf("Locations got %+v want %+v", got, want)
+	}
+
+	nearby, err := api.SearchMedia(ctx, 37.8029, -122.2721, 0)
+	if err != nil {
+		t.Fatalf("SearchMedia: %s", err)
+	}
+	if got, want := nearby.Medias[0].Location, oakland; got != want {
+		t.Errorf("Media location got %+v want %+v", got, want)
+	}
+
+	wantRequests := []string{
+		"/v1/locations/213051194 lat= lng= distance=",
+		"/v1/locations/213051194/media/recent lat= lng= distance=",
+		"/v1/locations/search lat=37.8029 lng=-122.2721 distance=750",
+		"/v1/media/search lat=37.8029 lng=-122.2721 distance=",
+	}
+	if !reflect.DeepEqual(requests, wantRequests) {
+		t.Errorf("Requests got %v want %v", requests, wantRequests)
+	}
+}
@@ -0,0 +1,44 @@
+package instagram
+
+import (
+	"log/slog"
+	"net/url"
+)
+
+// discardLogger is used when no Logger is configured.
+var discardLogger = slog.New(slog.DiscardHandler)
+
+// redactedParams are params whose values are never logged.
+var redactedParams = []string{"access_token", "sig", "client_id", "client_secret", "code", "state"}
+
+// redactParams returns a copy of v with secrets redacted, for logging.
+func redactParams(v url.Values) url.Values {
+	c := copyParams(v)
+	for _, k := range redactedParams {
+		if _, ok := c[k]; ok {
+			c.Set(k, "REDACTED")
+		}
+	}
+	return c
+}
+
+// redactURL returns u with secrets redacted from the query, for logging.
+func redactURL(u *url.URL) string {
+	r := *u
+	r.RawQuery = redactParams(u.Query()).Encode()
+	return r.String()
+}
+
+func (api *API) logger() *slog.Logger {
+	if api.Logger == nil {
+		return discardLogger
+	}
+	return api.Logger
+}
+
+func (o OAuth) logger() *slog.Logger {
+	if o.Logger == nil {
+		return discardLogger
+	}
+	return o.Logger
+}
@@ -0,0 +1,63 @@
+package instagram
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestLogger(t *testing.T) {
+	api, server := newTestAPIServer(t, func(r *http.Request) string {
+		return "image"
+	})
+	defer server.Close()
+
+	var buf bytes.Buffer
+	api.Logger = slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	api.AccessToken = "t0ken"
+	api.ClientSecret = "s3cret"
+
+	ctx := context.Background()
+	res, err := api.GetRecentMedia(ctx, nil)
+	if err != nil {
+		t.Fatalf("GetRecentMedia: %s", err)
+	}
+	if _, err := api.NextMedias(ctx, res.Pagination); err != nil {
+		t.Fatalf("NextMedias: %s", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{`msg="instagram: request"`, `msg="instagram: next page"`, "status=200"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Log does not contain %s:\n%s", want, out)
+		}
+	}
+	for _, secret := range []string{"t0ken", "s3cret", "9d69812c742548b7b797a90819402aa2"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("Log contains secret %s:\n%s", secret, out)
+		}
+	}
+}
+
+func TestOAuthLogger(t *testing.T) {
+	var buf bytes.Buffer
+	oauth := OAuth{
+		ClientID:    "cl1ent",
+		RedirectURI: "http://localhost/",
+		Logger:      slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})),
+	}
+	oauth.GetAuthorizeURL("st4te")
+
+	out := buf.String()
+	if !strings.Contains(out, "instagram: authorize url") {
+		t.Errorf("Log missing authorize url:\n%s", out)
+	}
+	for _, secret := range []string{"cl1ent", "st4te"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("Log contains secret %s:\n%s", secret, out)
+		}
+	}
+}
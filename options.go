@@ -0,0 +1,175 @@
+package instagram
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+)
+
+// Option configures an API created by NewClient.
+type Option func(*API) error
+
+// NewClient creates an API configured with opts. Either a client ID or an
+// access token is required. Access tokens are preferred because they keep
+// rate limiting down.
+func NewClient(opts ...Option) (*API, error) {
+	api := &API{
+		HTTPClient: &http.Client{},
+	}
+	for _, opt := range opts {
+		if err := opt(api); err != nil {
+			return nil, err
+		}
+	}
+	if api.ClientID == "" && api.AccessToken == "" && api.TokenSource == nil {
+		return nil, errors.New("instagram: client ID, access token or token source is required")
+	}
+	if api.EnforceSignedRequest && api.ClientSecret == "" {
+		return nil, errors.New("instagram: client secret is required for signed requests")
+	}
+	return api, nil
+}
+
+// WithClientID sets the client ID, used when there is no access token.
+func WithClientID(clientID string) Option {
+	return func(api *API) error {
+		api.ClientID = clientID
+		return nil
+	}
+}
+
+// WithClientSecret sets the client secret, used to sign requests.
+func WithClientSecret(clientSecret string) Option {
+	return func(api *API) error {
+		api.ClientSecret = clientSecret
+		return nil
+	}
+}
+
+// WithAccessToken sets the access token.
+func WithAccessToken(accessToken string) Option {
+	return func(api *API) error {
+		api.AccessToken = accessToken
+		return nil
+	}
+}
+
+// WithTokenSource sets the source of access tokens, for instance a
+// RefreshingTokenSource to refresh long-lived tokens.
+func WithTokenSource(ts TokenSource) Option {
+	return func(api *API) error {
+		if ts == nil {
+			return errors.New("instagram: token source is nil")
+		}
+		api.TokenSource = ts
+		return nil
+	}
+}
+
+// WithSignedRequests signs every request with the client secret.
+func WithSignedRequests() Option {
+	return func(api *API) error {
+		api.EnforceSignedRequest = true
+		return nil
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to make requests.
+func WithHTTPClient(c *http.Client) Option {
+	return func(api *API) error {
+		if c == nil {
+			return errors.New("instagram: HTTP client is nil")
+		}
+		api.HTTPClient = c
+		return nil
+	}
+}
+
+// WithBaseURL sets the base URL of the API, for instance to use a proxy.
+func WithBaseURL(baseURL string) Option {
+	return func(api *API) error {
+		if err := checkBaseURL(baseURL); err != nil {
+			return err
+		}
+		api.BaseURL = baseURL
+		return nil
+	}
+}
+
+func checkBaseURL(baseURL string) error {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return fmt.Errorf("instagram: invalid base URL: %s", err)
+	}
+	if !u.IsAbs() || u.Host == "" {
+		return fmt.Errorf("instagram: base URL %q is not absolute", baseURL)
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return fmt.Errorf("instagram: base URL %q has a query or fragment", baseURL)
+	}
+	return nil
+}
+
+// WithGraphBaseURL sets the base URL used by Graph.
+func WithGraphBaseURL(baseURL string) Option {
+	return func(api *API) error {
+		if err := checkBaseURL(baseURL); err != nil {
+			return err
+		}
+		api.GraphBaseURL = baseURL
+		return nil
+	}
+}
+
+// WithLogger sets the logger for debug events.
+func WithLogger(l *slog.Logger) Option {
+	return func(api *API) error {
+		api.Logger = l
+		return nil
+	}
+}
+
+// WithRetryPolicy sets how failed requests are retried.
+func WithRetryPolicy(p *RetryPolicy) Option {
+	return func(api *API) error {
+		if p != nil {
+			if p.MaxAttempts < 0 || p.BaseDelay < 0 || p.MaxDelay < 0 {
+				return errors.New("instagram: retry policy has a negative value")
+			}
+			if p.Jitter < 0 || p.Jitter > 1 {
+				return fmt.Errorf("instagram: retry jitter %v is not between 0 and 1", p.Jitter)
+			}
+		}
+		api.Retry = p
+		return nil
+	}
+}
+
+// WithRateLimiter sets the limiter that paces requests.
+func WithRateLimiter(l RateLimiter) Option {
+	return func(api *API) error {
+		api.RateLimiter = l
+		return nil
+	}
+}
+
+// WithCache sets the response cache.
+func WithCache(c *Cache) Option {
+	return func(api *API) error {
+		if c != nil && c.Store == nil {
+			return errors.New("instagram: cache has no store")
+		}
+		api.Cache = c
+		return nil
+	}
+}
+
+// WithInterceptors appends interceptors to the chain around each request.
+func WithInterceptors(ics ...Interceptor) Option {
+	return func(api *API) error {
+		api.Interceptors = append(api.Interceptors, ics...)
+		return nil
+	}
+}
@@ -0,0 +1,101 @@
+package instagram
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestNewClient(t *testing.T) {
+	tests := []struct {
+		desc    string
+		opts    []Option
+		wantErr string
+	}{
+		{
+			desc: "access token",
+			opts: []Option{WithAccessToken("t")},
+		},
+		{
+			desc: "signed",
+			opts: []Option{WithClientID("c"), WithClientSecret("s"), WithSignedRequests()},
+		},
+		{
+			desc: "all options",
+			opts: []Option{
+				WithAccessToken("t"),
+				WithHTTPClient(&http.Client{}),
+				WithBaseURL("http://localhost:8080/v1"),
+				WithLogger(discardLogger),
+				WithRetryPolicy(DefaultRetryPolicy()),
+				WithRateLimiter(&QuotaLimiter{}),
+				WithCache(&Cache{Store: NewMemoryCache(1)}),
+				WithInterceptors(),
+			},
+		},
+		{
+			desc: "token source",
+			opts: []Option{WithTokenSource(NewRefreshingTokenSource(OAuth{}, &Token{AccessToken: "t"}))},
+		},
+		{
+			desc:    "nil token source",
+			opts:    []Option{WithAccessToken("t"), WithTokenSource(nil)},
+			wantErr: "instagram: token source is nil",
+		},
+		{
+			desc:    "no credentials",
+			opts:    []Option{WithClientSecret("s")},
+			wantErr: "instagram: client ID, access token or token source is required",
+		},
+		{
+			desc:    "signed without secret",
+			opts:    []Option{WithAccessToken("t"), WithSignedRequests()},
+			wantErr: "instagram: client secret is required for signed requests",
+		},
+		{
+			desc:    "relative base URL",
+			opts:    []Option{WithAccessToken("t"), WithBaseURL("/v1")},
+			wantErr: `instagram: base URL "/v1" is not absolute`,
+		},
+		{
+			desc:    "nil HTTP client",
+			opts:    []Option{WithAccessToken("t"), WithHTTPClient(nil)},
+			wantErr: "instagram: HTTP client is nil",
+		},
+		{
+			desc:    "bad jitter",
+			opts:    []Option{WithAccessToken("t"), WithRetryPolicy(&RetryPolicy{Jitter: 2})},
+			wantErr: "instagram: retry jitter 2 is not between 0 and 1",
+		},
+		{
+			desc:    "cache without store",
+			opts:    []Option{WithAccessToken("t"), WithCache(&Cache{})},
+			wantErr: "instagram: cache has no store",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			api, err := NewClient(tt.opts...)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("Error got %v want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewClient: %s", err)
+			}
+			if api.HTTPClient == nil {
+				t.Errorf("HTTPClient is nil")
+			}
+		})
+	}
+}
+
+func TestNewPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Want panic")
+		}
+	}()
+	New("", "", "", false)
+}
@@ -3,44 +3,121 @@ package instagram
 import (
 	"context"
 	"net/url"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // NextMedias returns the next page of media
 func (api *API) NextMedias(ctx context.Context, mp MediaPagination) (res *PaginatedMediasResponse, err error) {
 	res = new(PaginatedMediasResponse)
-	err = api.next(ctx, mp.Pagination, res)
+	err = api.next(ctx, "NextMedias", mp.Pagination, res)
 	return
 }
 
-func (api *API) next(ctx context.Context, p Pagination, res interface{}) error {
-	done, uri, path, uriParams, err := p.NextPage()
+// NextComments returns the next page of comments
+func (api *API) NextComments(ctx context.Context, cp CommentPagination) (res *CommentsResponse, err error) {
+	res = new(CommentsResponse)
+	err = api.next(ctx, "NextComments", cp.Pagination, res)
+	return
+}
+
+// NextUsers returns the next page of users
+func (api *API) NextUsers(ctx context.Context, up UserPagination) (res *UsersResponse, err error) {
+	res = new(UsersResponse)
+	err = api.next(ctx, "NextUsers", up.Pagination, res)
+	return
+}
+
+func (api *API) next(ctx context.Context, endpoint string, p Pagination, res interface{}) error {
+	done, _, path, params, err := p.nextPage(api.baseURL())
 	if err != nil || done == true {
 		return err
 	}
 
-	// Sign params if using the secure api
-	if api.EnforceSignedRequest {
-		uriParams = signParams(path, uriParams, api.ClientSecret)
+	// Credentials are added again when the request is sent.
+	params.Del("access_token")
+	params.Del("client_id")
+
+	api.logger().DebugContext(ctx, "instagram: next page",
+		"endpoint", endpoint,
+		"path", path,
+		"params", redactParams(params).Encode())
+
+	return api.execute(ctx, &Request{
+		Endpoint: endpoint,
+		Method:   "GET",
+		Path:     path,
+		Params:   params,
+		Result:   res,
+	})
+}
+
+// NextPage returns the next page's uri and parameters. The uri is on the
+// base URL of the API that returned the page, for instance API.BaseURL, or
+// on the host of the next page URL if the Pagination wasn't returned by an
+// API.
+func (p Pagination) NextPage() (done bool, uri string, path string, params url.Values, err error) {
+	return p.page(p.NextURL)
+}
+
+// PreviousPage returns the previous page's uri and parameters, like
+// NextPage. Only the Graph API pages backward.
+func (p Pagination) PreviousPage() (done bool, uri string, path string, params url.Values, err error) {
+	return p.page(p.PreviousURL)
+}
+
+func (p Pagination) page(rawURL string) (done bool, uri string, path string, params url.Values, err error) {
+	if p.baseURL != "" {
+		return pageURL(rawURL, p.baseURL)
 	}
+	done, _, path, params, err = pageURL(rawURL, p.defaultBaseURL())
+	if done || err != nil {
+		return
+	}
+	u, _ := url.Parse(rawURL)
+	u.RawQuery = ""
+	u.Fragment = ""
+	uri = u.String()
+	return
+}
 
-	req, err := buildGetRequest(uri, uriParams)
-	if err != nil {
-		return err
+func (p Pagination) defaultBaseURL() string {
+	if p.graph {
+		return DefaultGraphURL
 	}
+	return DefaultBaseURL
+}
 
-	return api.do(ctx, req, res)
+// paginated is implemented by responses with a Pagination.
+type paginated interface {
+	pagination() *Pagination
 }
 
-// NextPage returns the next page's uri and parameters
-func (p Pagination) NextPage() (done bool, uri string, path string, params url.Values, err error) {
-	if p.NextURL == "" {
+// setPageBaseURL records the base URL that the pages of r are on.
+func setPageBaseURL(r interface{}, baseURL string) {
+	if pr, ok := r.(paginated); ok {
+		pr.pagination().baseURL = baseURL
+	}
+}
+
+func (p Pagination) nextPage(baseURL string) (done bool, uri string, path string, params url.Values, err error) {
+	return pageURL(p.NextURL, baseURL)
+}
+
+func (p Pagination) previousPage(baseURL string) (done bool, uri string, path string, params url.Values, err error) {
+	return pageURL(p.PreviousURL, baseURL)
+}
+
+// pageURL rebases the page URL built by the server onto baseURL.
+func pageURL(pageURL string, baseURL string) (done bool, uri string, path string, params url.Values, err error) {
+	if pageURL == "" {
 		// We're done. Theres no more pages
 		done = true
 		return
 	}
 
-	urlStruct, err := url.Parse(p.NextURL)
+	urlStruct, err := url.Parse(pageURL)
 	if err != nil {
 		return
 	}
@@ -48,10 +125,108 @@ func (p Pagination) NextPage() (done bool, uri string, path string, params url.V
 	params = urlStruct.Query()
 	// Remove `sig` key that was set by the initial request
 	params.Del("sig")
-	urlStruct.RawQuery = ""
 
 	done = false
-	path = strings.Replace(urlStruct.Path, "/v1", "", 1)
-	uri = urlStruct.String()
+	// Keep the path escaped, as it was sent and signed for the first page.
+	path = apiPath(urlStruct.EscapedPath(), baseURL)
+	uri = baseURL + path
+	return
+}
+
+// NextMedias returns the next page of media
+func (g *Graph) NextMedias(ctx context.Context, mp MediaPagination) (res *GraphMediasResponse, err error) {
+	res = new(GraphMediasResponse)
+	err = g.page(ctx, "NextMedias", mp.Pagination, Forward, res)
+	return
+}
+
+// PreviousMedias returns the previous page of media
+func (g *Graph) PreviousMedias(ctx context.Context, mp MediaPagination) (res *GraphMediasResponse, err error) {
+	res = new(GraphMediasResponse)
+	err = g.page(ctx, "PreviousMedias", mp.Pagination, Backward, res)
 	return
 }
+
+func (g *Graph) page(ctx context.Context, endpoint string, p Pagination, dir Direction, res interface{}) error {
+	page := p.nextPage
+	if dir == Backward {
+		page = p.previousPage
+	}
+	done, _, path, params, err := page(g.api.graphBaseURL())
+	if err != nil || done {
+		return err
+	}
+
+	// The access token is added again when the request is sent.
+	params.Del("access_token")
+
+	g.api.logger().DebugContext(ctx, "instagram: next page",
+		"endpoint", "Graph."+endpoint,
+		"path", path,
+		"params", redactParams(params).Encode())
+
+	return g.get(ctx, endpoint, path, params, res)
+}
+
+// Direction is the direction of paging.
+type Direction int
+
+const (
+	// Forward follows the next pages.
+	Forward Direction = iota
+	// Backward follows the previous pages.
+	Backward
+)
+
+// PageParams are the paging params of Graph API endpoints. Zero values are
+// left out.
+type PageParams struct {
+	// Limit is the number of results per page.
+	Limit int
+
+	// Before and After are the cursors of a Pagination, to resume paging.
+	Before string
+	After  string
+
+	// Since and Until limit the results of time-based endpoints, such as
+	// insights.
+	Since time.Time
+	Until time.Time
+}
+
+// Values returns the params, to pass to Graph methods.
+func (p PageParams) Values() url.Values {
+	params := url.Values{}
+	if p.Limit > 0 {
+		params.Set("limit", strconv.Itoa(p.Limit))
+	}
+	if p.Before != "" {
+		params.Set("before", p.Before)
+	}
+	if p.After != "" {
+		params.Set("after", p.After)
+	}
+	if !p.Since.IsZero() {
+		params.Set("since", strconv.FormatInt(p.Since.Unix(), 10))
+	}
+	if !p.Until.IsZero() {
+		params.Set("until", strconv.FormatInt(p.Until.Unix(), 10))
+	}
+	return params
+}
+
+// apiPath returns the part of urlPath after the path of baseURL. The next
+// page URL is built by the server, so if it doesn't share the path of
+// baseURL, for instance behind a proxy, DefaultBaseURL's path is tried.
+func apiPath(urlPath string, baseURL string) string {
+	for _, base := range []string{baseURL, DefaultBaseURL} {
+		u, err := url.Parse(base)
+		if err != nil || u.Path == "" {
+			continue
+		}
+		if strings.HasPrefix(urlPath, u.Path+"/") {
+			return strings.TrimPrefix(urlPath, u.Path)
+		}
+	}
+	return urlPath
+}
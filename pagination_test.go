@@ -0,0 +1,218 @@
+package instagram
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNextPage(t *testing.T) {
+	next := "https://api.instagram.com/v1/users/11073382793/media/recent?access_token=abc&count=1&max_id=123_456&sig=xyz"
+
+	tests := []struct {
+		desc     string
+		baseURL  string
+		wantURI  string
+		wantPath string
+	}{
+		{
+			desc:     "default",
+			baseURL:  DefaultBaseURL,
+			wantURI:  "https://api.instagram.com/v1/users/11073382793/media/recent",
+			wantPath: "/users/11073382793/media/recent",
+		},
+		{
+			desc:     "proxy",
+			baseURL:  "http://staging.example.com/instagram/v1",
+			wantURI:  "http://staging.example.com/instagram/v1/users/11073382793/media/recent",
+			wantPath: "/users/11073382793/media/recent",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			p := Pagination{NextURL: next}
+			done, uri, path, params, err := p.nextPage(tt.baseURL)
+			if err != nil {
+				t.Fatalf("nextPage: %s", err)
+			}
+			if done {
+				t.Fatalf("Want not done")
+			}
+			if uri != tt.wantURI {
+				t.Errorf("URI got %s want %s", uri, tt.wantURI)
+			}
+			if path != tt.wantPath {
+				t.Errorf("Path got %s want %s", path, tt.wantPath)
+			}
+			wantParams := url.Values{
+				"access_token": {"abc"},
+				"count":        {"1"},
+				"max_id":       {"123_456"},
+			}
+			if !reflect.DeepEqual(params, wantParams) {
+				t.Errorf("Params got %v want %v", params, wantParams)
+			}
+		})
+	}
+
+	done, _, _, _, err := Pagination{}.NextPage()
+	if !done || err != nil {
+		t.Errorf("Empty pagination got done %t err %v", done, err)
+	}
+}
+
+func TestGraphNextPage(t *testing.T) {
+	p := Pagination{
+		NextURL:     "https://graph.instagram.com/v12.0/17841405793187218/media?access_token=abc&limit=2&after=QVFIUjNyVmVB2",
+		PreviousURL: "https://graph.instagram.com/v12.0/17841405793187218/media?access_token=abc&limit=2&before=QVFIUkx0YWZA1",
+		graph:       true,
+	}
+
+	tests := []struct {
+		desc       string
+		page       func() (bool, string, string, url.Values, error)
+		wantURI    string
+		wantParams url.Values
+	}{
+		{
+			desc:       "next",
+			page:       p.NextPage,
+			wantURI:    "https://graph.instagram.com/v12.0/17841405793187218/media",
+			wantParams: url.Values{"access_token": {"abc"}, "limit": {"2"}, "after": {"QVFIUjNyVmVB2"}},
+		},
+		{
+			desc:       "previous",
+			page:       p.PreviousPage,
+			wantURI:    "https://graph.instagram.com/v12.0/17841405793187218/media",
+			wantParams: url.Values{"access_token": {"abc"}, "limit": {"2"}, "before": {"QVFIUkx0YWZA1"}},
+		},
+		{
+			desc: "versioned base",
+			page: func() (bool, string, string, url.Values, error) {
+				return p.nextPage("https://graph.example.com/v12.0")
+			},
+			wantURI:    "https://graph.example.com/v12.0/17841405793187218/media",
+			wantParams: url.Values{"access_token": {"abc"}, "limit": {"2"}, "after": {"QVFIUjNyVmVB2"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			done, uri, _, params, err := tt.page()
+			if err != nil || done {
+				t.Fatalf("page got done %t err %v", done, err)
+			}
+			if uri != tt.wantURI {
+				t.Errorf("URI got %s want %s", uri, tt.wantURI)
+			}
+			if !reflect.DeepEqual(params, tt.wantParams) {
+				t.Errorf("Params got %v want %v", params, tt.wantParams)
+			}
+		})
+	}
+
+	done, _, _, _, err := Pagination{graph: true}.PreviousPage()
+	if !done || err != nil {
+		t.Errorf("Empty pagination got done %t err %v", done, err)
+	}
+}
+
+func TestPageParams(t *testing.T) {
+	tests := []struct {
+		desc string
+		p    PageParams
+		want string
+	}{
+		{
+			desc: "empty",
+			want: "",
+		},
+		{
+			desc: "cursor",
+			p:    PageParams{Limit: 25, After: "QVFIUjNyVmVB2"},
+			want: "after=QVFIUjNyVmVB2&limit=25",
+		},
+		{
+			desc: "time range",
+			p: PageParams{
+				Since: time.Date(2019, 2, 1, 0, 0, 0, 0, time.UTC),
+				Until: time.Date(2019, 2, 15, 0, 0, 0, 0, time.UTC),
+			},
+			want: "since=1548979200&until=1550188800",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if got := tt.p.Values().Encode(); got != tt.want {
+				t.Errorf("Values got %s want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextMedias(t *testing.T) {
+	var paths []string
+	api, server := newTestAPIServer(t, func(r *http.Request) string {
+		paths = append(paths, r.URL.Path)
+		if len(paths) == 1 {
+			return "image"
+		}
+		return "video"
+	})
+	defer server.Close()
+
+	ctx := context.Background()
+	res, err := api.GetRecentMedia(ctx, nil)
+	if err != nil {
+		t.Fatalf("GetRecentMedia: %s", err)
+	}
+	res, err = api.NextMedias(ctx, res.Pagination)
+	if err != nil {
+		t.Fatalf("NextMedias: %s", err)
+	}
+	if got, want := res.Medias[0].Type, "video"; got != want {
+		t.Errorf("Media type got %s want %s", got, want)
+	}
+
+	wantPaths := []string{
+		"/v1/users/self/media/recent",
+		"/v1/users/11073382793/media/recent",
+	}
+	if !reflect.DeepEqual(paths, wantPaths) {
+		t.Errorf("Paths got %v want %v", paths, wantPaths)
+	}
+}
+
+func TestNextPageBaseURL(t *testing.T) {
+	api, server := newTestAPIServer(t, func(r *http.Request) string {
+		return "image"
+	})
+	defer server.Close()
+
+	res, err := api.GetRecentMedia(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("GetRecentMedia: %s", err)
+	}
+	_, uri, path, _, err := res.Pagination.NextPage()
+	if err != nil {
+		t.Fatalf("NextPage: %s", err)
+	}
+	if got, want := uri, server.URL+"/v1/users/11073382793/media/recent"; got != want {
+		t.Errorf("URI got %s want %s", got, want)
+	}
+	if got, want := path, "/users/11073382793/media/recent"; got != want {
+		t.Errorf("Path got %s want %s", got, want)
+	}
+
+	// Without an API, the host of the next page URL is kept.
+	p := Pagination{NextURL: "http://staging.example.com/v1/users/1/media/recent?max_id=2"}
+	_, uri, _, _, err = p.NextPage()
+	if err != nil {
+		t.Fatalf("NextPage: %s", err)
+	}
+	if got, want := uri, "http://staging.example.com/v1/users/1/media/recent"; got != want {
+		t.Errorf("URI got %s want %s", got, want)
+	}
+}
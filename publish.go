@@ -0,0 +1,355 @@
+package instagram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// PublishType is the type of a published media.
+type PublishType string
+
+// Types of published media. A carousel is published from items of type
+// PublishImage and PublishVideo.
+const (
+	PublishImage    PublishType = "IMAGE"
+	PublishVideo    PublishType = "VIDEO"
+	PublishReels    PublishType = "REELS"
+	PublishCarousel PublishType = "CAROUSEL"
+)
+
+// ContainerStatus is the status of a media container.
+type ContainerStatus string
+
+// Statuses of media containers. Only FINISHED containers can be published.
+const (
+	ContainerStatusInProgress ContainerStatus = "IN_PROGRESS"
+	ContainerStatusFinished   ContainerStatus = "FINISHED"
+	ContainerStatusPublished  ContainerStatus = "PUBLISHED"
+	ContainerStatusError      ContainerStatus = "ERROR"
+	ContainerStatusExpired    ContainerStatus = "EXPIRED"
+)
+
+// Container is a media container to create with CreateMediaContainer.
+type Container struct {
+	// Type is the type of the media. The default is PublishImage.
+	Type PublishType
+
+	// ImageURL is the public URL of the image of PublishImage.
+	ImageURL string
+
+	// VideoURL is the public URL of the video of PublishVideo and
+	// PublishReels.
+	VideoURL string
+
+	// Caption is the caption of the media. Carousel items have none.
+	Caption string
+
+	// IsCarouselItem is set for the items of a carousel.
+	IsCarouselItem bool
+
+	// Children are the IDs of the item containers of PublishCarousel.
+	Children []string
+}
+
+func (c Container) values() url.Values {
+	params := url.Values{}
+	if c.Type != "" && c.Type != PublishImage {
+		params.Set("media_type", string(c.Type))
+	}
+	if c.ImageURL != "" {
+		params.Set("image_url", c.ImageURL)
+	}
+	if c.VideoURL != "" {
+		params.Set("video_url", c.VideoURL)
+	}
+	if c.Caption != "" {
+		params.Set("caption", c.Caption)
+	}
+	if c.IsCarouselItem {
+		params.Set("is_carousel_item", "true")
+	}
+	if len(c.Children) > 0 {
+		params.Set("children", strings.Join(c.Children, ","))
+	}
+	return params
+}
+
+// CreateMediaContainer creates a container for media to publish with
+// PublishContainer. The media is downloaded from its URL, so wait for the
+// container to be FINISHED before publishing it.
+// Graph API: POST /{ig-user-id}/media
+func (g *Graph) CreateMediaContainer(ctx context.Context, userID string, c Container) (res *IDResponse, err error) {
+	res = new(IDResponse)
+	err = g.post(ctx, "CreateMediaContainer", fmt.Sprintf("/%s/media", url.PathEscape(userID)), c.values(), res)
+	return
+}
+
+// GetContainerStatus returns the status of a media container.
+// Graph API: GET /{ig-container-id}
+func (g *Graph) GetContainerStatus(ctx context.Context, containerID string) (res *ContainerStatusResponse, err error) {
+	res = new(ContainerStatusResponse)
+	params := url.Values{}
+	params.Set("fields", "id,status_code,status")
+	// The status is polled, so it must never come from the Cache.
+	err = g.execute(ctx, &Request{
+		Endpoint: "GetContainerStatus",
+		Method:   "GET",
+		Path:     "/" + url.PathEscape(containerID),
+		Params:   params,
+		Result:   res,
+		noCache:  true,
+	})
+	return
+}
+
+// DefaultPollPolicy returns the policy WaitForContainer polls with by
+// default. Videos may take minutes to process.
+func DefaultPollPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 30,
+		BaseDelay:   time.Second,
+		MaxDelay:    30 * time.Second,
+		Jitter:      0.2,
+	}
+}
+
+// WaitForContainer polls the status of a media container, with the backoff
+// of poll or DefaultPollPolicy, until it is FINISHED. It returns a
+// *ContainerError, with the last status, if the container fails or is not
+// finished after poll.MaxAttempts. OnRetry is not called.
+func (g *Graph) WaitForContainer(ctx context.Context, containerID string, poll *RetryPolicy) (res *ContainerStatusResponse, err error) {
+	res = new(ContainerStatusResponse)
+	if poll == nil {
+		poll = DefaultPollPolicy()
+	}
+	for attempt := 1; ; attempt++ {
+		res, err = g.GetContainerStatus(ctx, containerID)
+		if err != nil {
+			return
+		}
+		switch res.Status {
+		case ContainerStatusFinished:
+			return
+		case ContainerStatusInProgress, "":
+		default:
+			err = &ContainerError{ID: containerID, Status: res.Status, Message: res.Message}
+			return
+		}
+		if attempt >= poll.MaxAttempts {
+			err = &ContainerError{ID: containerID, Status: res.Status, Message: "not finished after polling"}
+			return
+		}
+		d := poll.backoff(attempt)
+		g.api.logger().DebugContext(ctx, "instagram: waiting for container",
+			"container", containerID,
+			"attempt", attempt,
+			"delay", d)
+		if err = sleep(ctx, d); err != nil {
+			return
+		}
+	}
+}
+
+// PublishContainer publishes a FINISHED media container and returns the ID
+// of the media.
+// Graph API: POST /{ig-user-id}/media_publish
+func (g *Graph) PublishContainer(ctx context.Context, userID string, containerID string) (res *IDResponse, err error) {
+	res = new(IDResponse)
+	params := url.Values{}
+	params.Set("creation_id", containerID)
+	err = g.post(ctx, "PublishContainer", fmt.Sprintf("/%s/media_publish", url.PathEscape(userID)), params, res)
+	return
+}
+
+// PublishItem is an image or video of a PublishRequest.
+type PublishItem struct {
+	// Type is PublishImage, PublishVideo or PublishReels. The default is
+	// PublishImage.
+	Type     PublishType
+	ImageURL string
+	VideoURL string
+}
+
+// PublishRequest is the media to publish with Publish.
+type PublishRequest struct {
+	// UserID is the business or creator account to publish as.
+	UserID string
+
+	Caption string
+
+	// Items are the images and videos. More than one item is published as
+	// a carousel, which can't contain reels.
+	Items []PublishItem
+
+	// Poll is the policy to wait for containers with. The default is
+	// DefaultPollPolicy.
+	Poll *RetryPolicy
+}
+
+// Publish publishes media in one call: it creates the containers, waits for
+// them to be FINISHED, publishes and returns the media. If it fails after
+// creating containers, the error is a *PublishError. Containers that are not
+// published expire after 24 hours.
+func (g *Graph) Publish(ctx context.Context, pr PublishRequest) (res *GraphMediaResponse, err error) {
+	res = new(GraphMediaResponse)
+	if len(pr.Items) == 0 {
+		err = errors.New("instagram: nothing to publish")
+		return
+	}
+	if len(pr.Items) > 1 {
+		for _, item := range pr.Items {
+			if item.Type == PublishReels {
+				err = errors.New("instagram: carousels can't contain reels")
+				return
+			}
+		}
+	}
+	p := &publisher{g: g, pr: pr}
+
+	var container Container
+	if len(pr.Items) == 1 {
+		container = pr.Items[0].container()
+	} else {
+		children, cerr := p.createItems(ctx)
+		if cerr != nil {
+			err = p.fail("create carousel items", cerr)
+			return
+		}
+		container = Container{Type: PublishCarousel, Children: children}
+	}
+	container.Caption = pr.Caption
+
+	id, err := p.create(ctx, container)
+	if err != nil {
+		err = p.fail("create container", err)
+		return
+	}
+	if _, err = g.WaitForContainer(ctx, id, pr.Poll); err != nil {
+		err = p.fail("wait for container", err)
+		return
+	}
+	published, err := g.PublishContainer(ctx, pr.UserID, id)
+	if err != nil {
+		err = p.fail("publish container", err)
+		return
+	}
+	p.mediaID = published.ID
+
+	res, err = g.GetMedia(ctx, published.ID)
+	if err != nil {
+		err = p.fail("get media", err)
+	}
+	return
+}
+
+func (i PublishItem) container() Container {
+	return Container{Type: i.Type, ImageURL: i.ImageURL, VideoURL: i.VideoURL}
+}
+
+// publisher keeps track of the containers created by Publish.
+type publisher struct {
+	g          *Graph
+	pr         PublishRequest
+	containers []string
+	mediaID    string
+}
+
+func (p *publisher) create(ctx context.Context, c Container) (string, error) {
+	res, err := p.g.CreateMediaContainer(ctx, p.pr.UserID, c)
+	if err != nil {
+		return "", err
+	}
+	p.containers = append(p.containers, res.ID)
+	return res.ID, nil
+}
+
+// createItems creates the item containers of a carousel, then waits for
+// all of them, so that videos are processed concurrently.
+func (p *publisher) createItems(ctx context.Context) ([]string, error) {
+	var ids []string
+	for _, item := range p.pr.Items {
+		c := item.container()
+		c.IsCarouselItem = true
+		id, err := p.create(ctx, c)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	for _, id := range ids {
+		if _, err := p.g.WaitForContainer(ctx, id, p.pr.Poll); err != nil {
+			return nil, err
+		}
+	}
+	return ids, nil
+}
+
+func (p *publisher) fail(step string, err error) error {
+	if len(p.containers) == 0 {
+		return err
+	}
+	return &PublishError{
+		Step:       step,
+		Containers: p.containers,
+		MediaID:    p.mediaID,
+		Err:        err,
+	}
+}
+
+// IDResponse is the Graph API response for CreateMediaContainer() and
+// PublishContainer()
+type IDResponse struct {
+	graphResponse
+	ID string `json:"id"`
+}
+
+// ContainerStatusResponse is the Graph API response for
+// GetContainerStatus()
+type ContainerStatusResponse struct {
+	graphResponse
+	ID     string          `json:"id"`
+	Status ContainerStatus `json:"status_code"`
+
+	// Message describes the status, for instance why it failed.
+	Message string `json:"status"`
+}
+
+// ContainerError is returned when a media container can't be published.
+type ContainerError struct {
+	ID      string
+	Status  ContainerStatus
+	Message string
+}
+
+func (e *ContainerError) Error() string {
+	return fmt.Sprintf("instagram: container %s is %s: %s", e.ID, e.Status, e.Message)
+}
+
+// PublishError is returned by Publish when it fails after creating
+// containers.
+type PublishError struct {
+	// Step is the step that failed.
+	Step string
+
+	// Containers are the IDs of the containers created, in order.
+	Containers []string
+
+	// MediaID is set if the media was published before the failure, in
+	// which case it must not be published again.
+	MediaID string
+
+	Err error
+}
+
+func (e *PublishError) Error() string {
+	return fmt.Sprintf("instagram: publish: %s: %s", e.Step, e.Err)
+}
+
+// Unwrap returns the error of the step that failed.
+func (e *PublishError) Unwrap() error {
+	return e.Err
+}
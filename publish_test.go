@@ -0,0 +1,291 @@
+package instagram
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// publishServer fakes the publishing endpoints. Containers are in progress
+// for the first status request.
+type publishServer struct {
+	mu        sync.Mutex
+	created   []url.Values
+	published []string
+	polls     int
+	status    string
+	media     string
+}
+
+func (s *publishServer) fixture(t *testing.T) func(r *http.Request) string {
+	return func(r *http.Request) string {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/17841405793187218/media":
+			r.ParseForm()
+			s.created = append(s.created, r.PostForm)
+			return "graph_container"
+		case r.Method == "POST" && r.URL.Path == "/17841405793187218/media_publish":
+			s.published = append(s.published, r.PostFormValue("creation_id"))
+			return "graph_published"
+		case r.Method == "GET" && r.URL.Path == "/17889615691921648":
+			s.polls++
+			if s.polls == 1 {
+				return "graph_container_in_progress"
+			}
+			return s.status
+		case r.Method == "GET" && r.URL.Path == "/17895695668004550":
+			return s.media
+		}
+		return ""
+	}
+}
+
+var testPoll = &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+
+func TestPublish(t *testing.T) {
+	tests := []struct {
+		desc        string
+		items       []PublishItem
+		wantCreated []url.Values
+		wantPolls   int
+	}{
+		{
+			desc:  "image",
+			items: []PublishItem{{ImageURL: "https://example.com/a.jpg"}},
+			wantCreated: []url.Values{
+				{"access_token": {"t0ken"}, "image_url": {"https://example.com/a.jpg"}, "caption": {"#0219test"}},
+			},
+			wantPolls: 2,
+		},
+		{
+			desc:  "reels",
+			items: []PublishItem{{Type: PublishReels, VideoURL: "https://example.com/a.mp4"}},
+			wantCreated: []url.Values{
+				{"access_token": {"t0ken"}, "media_type": {"REELS"}, "video_url": {"https://example.com/a.mp4"}, "caption": {"#0219test"}},
+			},
+			wantPolls: 2,
+		},
+		{
+			desc: "carousel",
+			items: []PublishItem{
+				{ImageURL: "https://example.com/a.jpg"},
+				{Type: PublishVideo, VideoURL: "https://example.com/b.mp4"},
+			},
+			wantCreated: []url.Values{
+				{"access_token": {"t0ken"}, "image_url": {"https://example.com/a.jpg"}, "is_carousel_item": {"true"}},
+				{"access_token": {"t0ken"}, "media_type": {"VIDEO"}, "video_url": {"https://example.com/b.mp4"}, "is_carousel_item": {"true"}},
+				{"access_token": {"t0ken"}, "media_type": {"CAROUSEL"}, "children": {"17889615691921648,17889615691921648"}, "caption": {"#0219test"}},
+			},
+			wantPolls: 4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			s := &publishServer{status: "graph_container_finished", media: "graph_media"}
+			g, server := newTestGraphServer(t, s.fixture(t))
+			defer server.Close()
+
+			res, err := g.Publish(context.Background(), PublishRequest{
+				UserID:  "17841405793187218",
+				Caption: "#0219test",
+				Items:   tt.items,
+				Poll:    testPoll,
+			})
+			if err != nil {
+				t.Fatalf("Publish: %s", err)
+			}
+			if got, want := res.Media.ID, "17895695668004550"; got != want {
+				t.Errorf("Media ID got %s want %s", got, want)
+			}
+			if !reflect.DeepEqual(s.created, tt.wantCreated) {
+				t.Errorf("Created got %v want %v", s.created, tt.wantCreated)
+			}
+			if got, want := s.published, []string{"17889615691921648"}; !reflect.DeepEqual(got, want) {
+				t.Errorf("Published got %v want %v", got, want)
+			}
+			if got := s.polls; got != tt.wantPolls {
+				t.Errorf("Polls got %d want %d", got, tt.wantPolls)
+			}
+		})
+	}
+}
+
+func TestPublishWithCache(t *testing.T) {
+	s := &publishServer{status: "graph_container_finished", media: "graph_media"}
+	g, server := newTestGraphServer(t, s.fixture(t))
+	defer server.Close()
+	g.api.Cache = &Cache{Store: NewMemoryCache(10), DefaultTTL: time.Minute}
+
+	res, err := g.Publish(context.Background(), PublishRequest{
+		UserID: "17841405793187218",
+		Items:  []PublishItem{{ImageURL: "https://example.com/a.jpg"}},
+		Poll:   testPoll,
+	})
+	if err != nil {
+		t.Fatalf("Publish: %s", err)
+	}
+	if got, want := res.Media.ID, "17895695668004550"; got != want {
+		t.Errorf("Media ID got %s want %s", got, want)
+	}
+	if got, want := s.polls, 2; got != want {
+		t.Errorf("Polls got %d want %d", got, want)
+	}
+}
+
+func TestPublishFailure(t *testing.T) {
+	tests := []struct {
+		desc          string
+		status        string
+		media         string
+		wantStep      string
+		wantMediaID   string
+		wantErr       error
+		wantContainer *ContainerError
+	}{
+		{
+			desc:     "container error",
+			status:   "graph_container_error",
+			wantStep: "wait for container",
+			wantContainer: &ContainerError{
+				ID:      "17889615691921648",
+				Status:  ContainerStatusError,
+				Message: "Error: Media download has failed. The media URI doesn't meet our requirements.",
+			},
+		},
+		{
+			desc:     "not finished",
+			status:   "graph_container_in_progress",
+			wantStep: "wait for container",
+			wantContainer: &ContainerError{
+				ID:      "17889615691921648",
+				Status:  ContainerStatusInProgress,
+				Message: "not finished after polling",
+			},
+		},
+		{
+			desc:        "published but not found",
+			status:      "graph_container_finished",
+			media:       "graph_not_found",
+			wantStep:    "get media",
+			wantMediaID: "17895695668004550",
+			wantErr:     ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			s := &publishServer{status: tt.status, media: tt.media}
+			g, server := newTestGraphServer(t, s.fixture(t))
+			defer server.Close()
+
+			res, err := g.Publish(context.Background(), PublishRequest{
+				UserID: "17841405793187218",
+				Items:  []PublishItem{{ImageURL: "https://example.com/a.jpg"}},
+				Poll:   testPoll,
+			})
+			if res == nil {
+				t.Errorf("Response got nil with error")
+			}
+			var perr *PublishError
+			if !errors.As(err, &perr) {
+				t.Fatalf("Error got %v want *PublishError", err)
+			}
+			if got := perr.Step; got != tt.wantStep {
+				t.Errorf("Step got %q want %q", got, tt.wantStep)
+			}
+			if got, want := perr.Containers, []string{"17889615691921648"}; !reflect.DeepEqual(got, want) {
+				t.Errorf("Containers got %v want %v", got, want)
+			}
+			if got := perr.MediaID; got != tt.wantMediaID {
+				t.Errorf("MediaID got %q want %q", got, tt.wantMediaID)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Error got %v want %v", err, tt.wantErr)
+			}
+			if tt.wantContainer != nil {
+				var cerr *ContainerError
+				if !errors.As(err, &cerr) {
+					t.Fatalf("Error got %v want *ContainerError", err)
+				}
+				if !reflect.DeepEqual(cerr, tt.wantContainer) {
+					t.Errorf("ContainerError got %#v want %#v", cerr, tt.wantContainer)
+				}
+			}
+		})
+	}
+}
+
+func TestWaitForContainerError(t *testing.T) {
+	s := &publishServer{status: "graph_container_error"}
+	g, server := newTestGraphServer(t, s.fixture(t))
+	defer server.Close()
+
+	res, err := g.WaitForContainer(context.Background(), "17889615691921648", testPoll)
+	var cerr *ContainerError
+	if !errors.As(err, &cerr) {
+		t.Fatalf("Error got %v want *ContainerError", err)
+	}
+	if got, want := res.Status, ContainerStatusError; got != want {
+		t.Errorf("Status got %s want %s", got, want)
+	}
+	if got, want := res.Response.StatusCode, 200; got != want {
+		t.Errorf("StatusCode got %d want %d", got, want)
+	}
+}
+
+func TestPublishCancel(t *testing.T) {
+	s := &publishServer{status: "graph_container_in_progress"}
+	g, server := newTestGraphServer(t, s.fixture(t))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	_, err := g.Publish(ctx, PublishRequest{
+		UserID: "17841405793187218",
+		Items:  []PublishItem{{ImageURL: "https://example.com/a.jpg"}},
+		Poll:   &RetryPolicy{MaxAttempts: 100, BaseDelay: time.Hour},
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Error got %v want %v", err, context.Canceled)
+	}
+	if len(s.published) != 0 {
+		t.Errorf("Published got %v want none", s.published)
+	}
+}
+
+func TestPublishInvalid(t *testing.T) {
+	g := NewGraph(&API{AccessToken: "t0ken", HTTPClient: &http.Client{}})
+	tests := []struct {
+		desc  string
+		items []PublishItem
+	}{
+		{
+			desc: "no items",
+		},
+		{
+			desc: "reels in carousel",
+			items: []PublishItem{
+				{ImageURL: "https://example.com/a.jpg"},
+				{Type: PublishReels, VideoURL: "https://example.com/b.mp4"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			res, err := g.Publish(context.Background(), PublishRequest{UserID: "1", Items: tt.items})
+			if err == nil || res == nil {
+				t.Errorf("Publish got %v, %v want response and error", res, err)
+			}
+		})
+	}
+}
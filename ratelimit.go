@@ -0,0 +1,110 @@
+package instagram
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimit is the request quota reported by the X-Ratelimit-Limit and
+// X-Ratelimit-Remaining response headers.
+type RateLimit struct {
+	Limit     int
+	Remaining int
+
+	// Time is when the quota was reported.
+	Time time.Time
+}
+
+// Known returns true if a quota was reported.
+func (r RateLimit) Known() bool {
+	return r.Limit > 0
+}
+
+func parseRateLimit(h http.Header, now time.Time) RateLimit {
+	var r RateLimit
+	limit, err := strconv.Atoi(h.Get("X-Ratelimit-Limit"))
+	if err != nil {
+		return r
+	}
+	remaining, err := strconv.Atoi(h.Get("X-Ratelimit-Remaining"))
+	if err != nil {
+		return r
+	}
+	r.Limit = limit
+	r.Remaining = remaining
+	r.Time = now
+	return r
+}
+
+// RateLimiter paces requests so that they stay within the API quota.
+type RateLimiter interface {
+	// Wait blocks until a request may be made, or ctx is done. last is
+	// the quota reported by the most recent response, less any requests
+	// made since.
+	Wait(ctx context.Context, last RateLimit) error
+}
+
+// QuotaLimiter is a RateLimiter that blocks once the remaining quota falls to
+// Reserve, until the quota window has passed.
+type QuotaLimiter struct {
+	// Window is the period over which the quota replenishes. The
+	// default is one hour, the window used by the API.
+	Window time.Duration
+
+	// Reserve is the number of requests to hold back, for instance for
+	// interactive use while a batch job runs.
+	Reserve int
+}
+
+// Wait implements RateLimiter.
+func (l *QuotaLimiter) Wait(ctx context.Context, last RateLimit) error {
+	if !last.Known() || last.Remaining > l.Reserve {
+		return nil
+	}
+	window := l.Window
+	if window == 0 {
+		window = time.Hour
+	}
+	d := time.Until(last.Time.Add(window))
+	if d <= 0 {
+		return nil
+	}
+	return sleep(ctx, d)
+}
+
+// quota tracks the most recently reported RateLimit.
+type quota struct {
+	mu   sync.Mutex
+	last RateLimit
+}
+
+func (q *quota) get() RateLimit {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.last
+}
+
+func (q *quota) update(r RateLimit) {
+	if !r.Known() {
+		return
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if r.Time.Before(q.last.Time) {
+		return
+	}
+	q.last = r
+}
+
+// take records a request made since the last update, so that concurrent
+// callers don't all spend the same remaining quota.
+func (q *quota) take() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.last.Known() && q.last.Remaining > 0 {
+		q.last.Remaining--
+	}
+}
@@ -0,0 +1,130 @@
+package instagram
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseRateLimit(t *testing.T) {
+	now := time.Date(2019, 2, 14, 19, 43, 40, 0, time.UTC)
+	tests := []struct {
+		desc      string
+		limit     string
+		remaining string
+		want      RateLimit
+	}{
+		{
+			desc: "missing",
+			want: RateLimit{},
+		},
+		{
+			desc:      "present",
+			limit:     "5000",
+			remaining: "0",
+			want:      RateLimit{Limit: 5000, Remaining: 0, Time: now},
+		},
+		{
+			desc:      "malformed",
+			limit:     "5000",
+			remaining: "lots",
+			want:      RateLimit{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			h := http.Header{}
+			if tt.limit != "" {
+				h.Set("X-Ratelimit-Limit", tt.limit)
+			}
+			if tt.remaining != "" {
+				h.Set("X-Ratelimit-Remaining", tt.remaining)
+			}
+			if got, want := parseRateLimit(h, now), tt.want; got != want {
+				t.Errorf("RateLimit got %+v want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestAPIRateLimit(t *testing.T) {
+	api, server := newTestAPIServer(t, func(r *http.Request) string {
+		return "comments"
+	})
+	defer server.Close()
+
+	if api.RateLimit().Known() {
+		t.Errorf("RateLimit known before any request")
+	}
+
+	ctx := context.Background()
+	if _, err := api.GetMediaRecentComments(ctx, "123", nil); err != nil {
+		t.Fatalf("GetMediaRecentComments: %s", err)
+	}
+
+	got := api.RateLimit()
+	if got.Limit != 5000 || got.Remaining != 4999 {
+		t.Errorf("RateLimit got %+v want 4999 of 5000", got)
+	}
+}
+
+func TestQuotaLimiter(t *testing.T) {
+	l := &QuotaLimiter{Reserve: 10}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	tests := []struct {
+		desc    string
+		last    RateLimit
+		wantErr error
+	}{
+		{
+			desc: "unknown",
+			last: RateLimit{},
+		},
+		{
+			desc: "above reserve",
+			last: RateLimit{Limit: 5000, Remaining: 11, Time: time.Now()},
+		},
+		{
+			desc: "window passed",
+			last: RateLimit{Limit: 5000, Remaining: 0, Time: time.Now().Add(-2 * time.Hour)},
+		},
+		{
+			desc:    "at reserve",
+			last:    RateLimit{Limit: 5000, Remaining: 10, Time: time.Now()},
+			wantErr: context.DeadlineExceeded,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if got, want := l.Wait(ctx, tt.last), tt.wantErr; got != want {
+				t.Errorf("Wait got %v want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestQuotaTake(t *testing.T) {
+	var q quota
+	q.take()
+	if q.get().Known() {
+		t.Fatalf("take changed an unknown quota")
+	}
+
+	now := time.Now()
+	q.update(RateLimit{Limit: 10, Remaining: 2, Time: now})
+	q.take()
+	q.take()
+	q.take()
+	if got, want := q.get().Remaining, 0; got != want {
+		t.Errorf("Remaining got %d want %d", got, want)
+	}
+
+	q.update(RateLimit{Limit: 10, Remaining: 8, Time: now.Add(-time.Second)})
+	if got, want := q.get().Remaining, 0; got != want {
+		t.Errorf("Stale update applied: Remaining got %d want %d", got, want)
+	}
+}
@@ -0,0 +1,107 @@
+package instagram
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// GetFollows returns the users the authenticated user follows.
+// Requires scope: follower_list.
+// REST API: GET /users/self/follows
+func (api *API) GetFollows(ctx context.Context, params url.Values) (res *UsersResponse, err error) {
+	res = new(UsersResponse)
+	err = api.get(ctx, "GetFollows", "/users/self/follows", params, res)
+	return
+}
+
+// GetFollowedBy returns the users who follow the authenticated user.
+// Requires scope: follower_list.
+// REST API: GET /users/self/followed-by
+func (api *API) GetFollowedBy(ctx context.Context, params url.Values) (res *UsersResponse, err error) {
+	res = new(UsersResponse)
+	err = api.get(ctx, "GetFollowedBy", "/users/self/followed-by", params, res)
+	return
+}
+
+// GetRequestedBy returns the users who have requested to follow the
+// authenticated user.
+// Requires scope: follower_list.
+// REST API: GET /users/self/requested-by
+func (api *API) GetRequestedBy(ctx context.Context, params url.Values) (res *UsersResponse, err error) {
+	res = new(UsersResponse)
+	err = api.get(ctx, "GetRequestedBy", "/users/self/requested-by", params, res)
+	return
+}
+
+// GetRelationship returns the relationship between the authenticated user and
+// another user.
+// Requires scope: follower_list.
+// REST API: GET /users/{user-id}/relationship
+func (api *API) GetRelationship(ctx context.Context, userID string) (res *RelationshipResponse, err error) {
+	res = new(RelationshipResponse)
+	err = api.get(ctx, "GetRelationship", fmt.Sprintf("/users/%s/relationship", url.PathEscape(userID)), nil, res)
+	return
+}
+
+// CollectUsers returns the users of every page starting with res, for
+// instance a snapshot of all followers from GetFollowedBy.
+func (api *API) CollectUsers(ctx context.Context, res *UsersResponse) ([]User, error) {
+	var users []User
+	userc, errc := api.IterateUsers(ctx, res)
+	for u := range userc {
+		users = append(users, *u)
+	}
+	if err := <-errc; err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// UserDiff is the difference between two snapshots of a list of users.
+type UserDiff struct {
+	// Added are in the new snapshot but not the old.
+	Added []User
+
+	// Removed are in the old snapshot but not the new.
+	Removed []User
+}
+
+// DiffUsers compares two snapshots of a list of users, such as followers
+// collected at different times. Users are identified by ID, or by Username
+// if they have no ID. Users keep the order of their snapshot.
+func DiffUsers(before, after []User) UserDiff {
+	inBefore := userKeys(before)
+	inAfter := userKeys(after)
+
+	var diff UserDiff
+	for _, u := range after {
+		if !inBefore[userKey(u)] {
+			diff.Added = append(diff.Added, u)
+		}
+	}
+	for _, u := range before {
+		if !inAfter[userKey(u)] {
+			diff.Removed = append(diff.Removed, u)
+		}
+	}
+	return diff
+}
+
+func userKey(u User) string {
+	if u.ID != "" {
+		return "id:" + u.ID
+	}
+	return "username:" + u.Username
+}
+
+func userKeys(users []User) map[string]bool {
+	keys := make(map[string]bool, len(users))
+	for _, u := range users {
+		keys[userKey(u)] = true
+	}
+	return keys
+}
@@ -0,0 +1,131 @@
+package instagram
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/kr/pretty"
+)
+
+func TestRelationships(t *testing.T) {
+	var paths []string
+	api, server := newTestAPIServer(t, func(r *http.Request) string {
+		paths = append(paths, r.URL.Path)
+		switch r.URL.Path {
+		case "/v1/users/11073382793/relationship":
+			return "relationship"
+		case "/v1/users/self/followed-by":
+			if r.URL.Query().Get("cursor") != "" {
+				return "follows_last"
+			}
+		}
+		return "follows"
+	})
+	defer server.Close()
+
+	ctx := context.Background()
+
+	for _, call := range []func(context.Context) (*UsersResponse, error){
+		func(ctx context.Context) (*UsersResponse, error) { return api.GetFollows(ctx, nil) },
+		func(ctx context.Context) (*UsersResponse, error) { return api.GetRequestedBy(ctx, nil) },
+	} {
+		res, err := call(ctx)
+		if err != nil {
+			t.Fatalf("%s: %s", paths[len(paths)-1], err)
+		}
+		if got, want := len(res.Users), 2; got != want {
+			t.Errorf("%s users got %d want %d", paths[len(paths)-1], got, want)
+		}
+	}
+
+	rel, err := api.GetRelationship(ctx, "11073382793")
+	if err != nil {
+		t.Fatalf("GetRelationship: %s", err)
+	}
+	wantRel := Relationship{
+		OutgoingStatus: "follows",
+		IncomingStatus: "requested_by",
+	}
+	if got, want := *rel.Relationship, wantRel; got != want {
+		t.Errorf("Relationship got %+v want %+v", got, want)
+	}
+
+	res, err := api.GetFollowedBy(ctx, nil)
+	if err != nil {
+		t.Fatalf("GetFollowedBy: %s", err)
+	}
+	followers, err := api.CollectUsers(ctx, res)
+	if err != nil {
+		t.Fatalf("CollectUsers: %s", err)
+	}
+	var ids []string
+	for _, u := range followers {
+		ids = append(ids, u.ID)
+	}
+	if got, want := ids, []string{"1574083", "11073382793", "3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Follower IDs got %v want %v", got, want)
+	}
+
+	wantPaths := []string{
+		"/v1/users/self/follows",
+		"/v1/users/self/requested-by",
+		"/v1/users/11073382793/relationship",
+		"/v1/users/self/followed-by",
+		"/v1/users/self/followed-by",
+	}
+	if !reflect.DeepEqual(paths, wantPaths) {
+		t.Errorf("Paths got %v want %v", paths, wantPaths)
+	}
+}
+
+func TestDiffUsers(t *testing.T) {
+	alice := User{ID: "1", Username: "alice"}
+	bob := User{ID: "2", Username: "bob"}
+	carol := User{ID: "3", Username: "carol"}
+	bobRenamed := User{ID: "2", Username: "robert"}
+	noID := User{Username: "rcarver"}
+
+	tests := []struct {
+		desc   string
+		before []User
+		after  []User
+		want   UserDiff
+	}{
+		{
+			desc: "empty",
+		},
+		{
+			desc:   "no change",
+			before: []User{alice, bob},
+			after:  []User{bob, alice},
+		},
+		{
+			desc:   "added and removed",
+			before: []User{alice, bob},
+			after:  []User{bob, carol, noID},
+			want: UserDiff{
+				Added:   []User{carol, noID},
+				Removed: []User{alice},
+			},
+		},
+		{
+			desc:   "renamed user is the same user",
+			before: []User{bob, noID},
+			after:  []User{bobRenamed},
+			want: UserDiff{
+				Removed: []User{noID},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got := DiffUsers(tt.before, tt.after)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff got %+v want %+v", got, tt.want)
+				pretty.Ldiff(t, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,7 +1,30 @@
 package instagram
 
+import (
+	"net/http"
+	"time"
+)
+
 type metaResponse struct {
 	Meta *Meta
+
+	// Response is metadata about the HTTP response to this call.
+	Response *Response `json:"-"`
+}
+
+func (m *metaResponse) setResponse(r *Response) {
+	m.Response = r
+}
+
+// responder is implemented by API responses that record their Response.
+type responder interface {
+	setResponse(*Response)
+}
+
+// EmptyResponse is the API response for endpoints that return no data, such
+// as LikeMedia()
+type EmptyResponse struct {
+	metaResponse
 }
 
 // UserResponse is the API response for GetSelf()
@@ -10,6 +33,36 @@ type UserResponse struct {
 	User *User `json:"data"`
 }
 
+// UsersResponse is the API response for GetMediaLikes(), SearchUsers() and
+// the relationship endpoints such as GetFollows()
+type UsersResponse struct {
+	metaResponse
+	Users      []User `json:"data"`
+	Pagination UserPagination
+}
+
+func (r *UsersResponse) pagination() *Pagination {
+	return &r.Pagination.Pagination
+}
+
+// UserPagination will give you an easy way to request the next page of
+// users.
+type UserPagination struct {
+	Pagination
+}
+
+// RelationshipResponse is the API response for GetRelationship()
+type RelationshipResponse struct {
+	metaResponse
+	Relationship *Relationship `json:"data"`
+}
+
+// MediaResponse is the API response for GetMedia()
+type MediaResponse struct {
+	metaResponse
+	Media *Media `json:"data"`
+}
+
 // PaginatedMediasResponse is the API response for GetRecentMedia()
 type PaginatedMediasResponse struct {
 	metaResponse
@@ -17,6 +70,10 @@ type PaginatedMediasResponse struct {
 	Pagination MediaPagination
 }
 
+func (r *PaginatedMediasResponse) pagination() *Pagination {
+	return &r.Pagination.Pagination
+}
+
 // MediaPagination will give you an easy way to request the next page of media.
 type MediaPagination struct {
 	Pagination
@@ -25,13 +82,65 @@ type MediaPagination struct {
 // CommentsResponse is the API response for GetMediaRecentComments()
 type CommentsResponse struct {
 	metaResponse
-	Comments []Comment `json:"data"`
+	Comments   []Comment `json:"data"`
+	Pagination CommentPagination
+}
+
+func (r *CommentsResponse) pagination() *Pagination {
+	return &r.Pagination.Pagination
+}
+
+// CommentPagination will give you an easy way to request the next page of
+// comments.
+type CommentPagination struct {
+	Pagination
+}
+
+// TagResponse is the API response for GetTag()
+type TagResponse struct {
+	metaResponse
+	Tag *Tag `json:"data"`
+}
+
+// TagsResponse is the API response for SearchTags()
+type TagsResponse struct {
+	metaResponse
+	Tags []Tag `json:"data"`
+}
+
+// LocationResponse is the API response for GetLocation()
+type LocationResponse struct {
+	metaResponse
+	Location *Location `json:"data"`
+}
+
+// LocationsResponse is the API response for SearchLocations()
+type LocationsResponse struct {
+	metaResponse
+	Locations []Location `json:"data"`
 }
 
 // Pagination describes how to get the next page of results.
 type Pagination struct {
 	NextURL   string `json:"next_url"`
 	NextMaxID string `json:"next_max_id"`
+
+	// NextMaxTagID and MinTagID are set by GetTagRecentMedia. Pass
+	// MinTagID as the min_tag_id param to get only newer media.
+	NextMaxTagID string `json:"next_max_tag_id,omitempty"`
+	MinTagID     string `json:"min_tag_id,omitempty"`
+
+	// PreviousURL, Before and After are set by the Graph API, which pages
+	// with cursors in both directions. Pass Before or After in PageParams
+	// to resume paging later.
+	PreviousURL string `json:"-"`
+	Before      string `json:"-"`
+	After       string `json:"-"`
+
+	graph bool
+
+	// baseURL is the base URL of the API that returned the page.
+	baseURL string
 }
 
 // Meta is the response information.
@@ -40,3 +149,27 @@ type Meta struct {
 	ErrorType    string `json:"error_type"`
 	ErrorMessage string `json:"error_message"`
 }
+
+// Response is metadata about the HTTP response to a single API call.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+
+	// RawBody is the undecoded response body. It is only set when
+	// API.KeepRawBody is true.
+	RawBody []byte
+
+	// RateLimit is the quota reported with the response.
+	RateLimit RateLimit
+
+	// Cache is whether the response came from the API's Cache.
+	Cache CacheStatus
+}
+
+func newResponse(resp *http.Response) *Response {
+	return &Response{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		RateLimit:  parseRateLimit(resp.Header, time.Now()),
+	}
+}
@@ -0,0 +1,156 @@
+package instagram
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy configures how failed requests are retried. Only GET requests
+// are retried, and only after a transient network error, a 5xx or 429
+// response, or a rate limit error.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	// Values less than 2 disable retries.
+	MaxAttempts int
+
+	// BaseDelay is the delay before the first retry. The delay doubles
+	// with each subsequent retry.
+	BaseDelay time.Duration
+
+	// MaxDelay caps the delay between attempts. Zero means no cap.
+	MaxDelay time.Duration
+
+	// Jitter is the fraction, between 0 and 1, of each delay that is
+	// randomized so that concurrent clients don't retry in lockstep.
+	Jitter float64
+
+	// OnRetry, if set, is called before waiting for each retry.
+	OnRetry func(RetryEvent)
+}
+
+// RetryEvent describes a retry that is about to happen.
+type RetryEvent struct {
+	// Attempt is the attempt that failed, starting at 1.
+	Attempt int
+
+	// Delay is how long until the next attempt.
+	Delay time.Duration
+
+	// Err is the error from the failed attempt.
+	Err error
+
+	// Request is the HTTP request being retried.
+	Request *http.Request
+}
+
+// DefaultRetryPolicy returns a policy suitable for long running jobs such as
+// walking all media with IterateMedia.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 4,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    30 * time.Second,
+		Jitter:      0.2,
+	}
+}
+
+// delay returns how long to wait before retrying the request after attempt
+// failed with err. It returns false if the request should not be retried.
+func (p *RetryPolicy) delay(ctx context.Context, req *http.Request, attempt int, err error, resp *Response) (time.Duration, bool) {
+	if p == nil || attempt >= p.MaxAttempts {
+		return 0, false
+	}
+	if req.Method != "GET" || ctx.Err() != nil || !isRetryable(err) {
+		return 0, false
+	}
+
+	d, ok := retryAfter(resp)
+	if !ok {
+		d = p.backoff(attempt)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && time.Now().Add(d).After(deadline) {
+		return 0, false
+	}
+	return d, true
+}
+
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.BaseDelay
+	for i := 1; i < attempt; i++ {
+		d *= 2
+		if p.MaxDelay > 0 && d >= p.MaxDelay {
+			break
+		}
+	}
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	if p.Jitter > 0 {
+		d -= time.Duration(p.Jitter * rand.Float64() * float64(d))
+	}
+	return d
+}
+
+func isRetryable(err error) bool {
+	var r interface{ Retryable() bool }
+	if errors.As(err, &r) {
+		return r.Retryable()
+	}
+	return isTransient(err)
+}
+
+// isTransient returns whether err is a network error that may not happen
+// again: a timeout, or a connection that was refused, reset or cut short.
+// Other errors of the HTTP client, such as TLS errors or malformed URLs, are
+// permanent.
+func isTransient(err error) bool {
+	if errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	var ne net.Error
+	return errors.As(err, &ne) && ne.Timeout()
+}
+
+// retryAfter parses the Retry-After header, in either seconds or HTTP date
+// form.
+func retryAfter(resp *Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
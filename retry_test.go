@@ -0,0 +1,253 @@
+package instagram
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func newRetryTestAPI(statuses ...int) (*API, *int, func()) {
+	mux, server := initTestServer()
+
+	var calls int
+	mux.HandleFunc("/v1/", func(w http.ResponseWriter, r *http.Request) {
+		status := statuses[len(statuses)-1]
+		if calls < len(statuses) {
+			status = statuses[calls]
+		}
+		calls++
+		w.WriteHeader(status)
+		if status == 200 {
+			fmt.Fprint(w, `{"meta":{"code":200},"data":{"id":"1"}}`)
+		} else {
+			fmt.Fprintf(w, `{"meta":{"code":%d,"error_type":"APIError"}}`, status)
+		}
+	})
+
+	api := &API{
+		BaseURL:    server.URL + "/v1",
+		HTTPClient: server.Client(),
+		Retry: &RetryPolicy{
+			MaxAttempts: 3,
+			BaseDelay:   time.Millisecond,
+		},
+	}
+	return api, &calls, server.Close
+}
+
+func TestRetry(t *testing.T) {
+	tests := []struct {
+		desc      string
+		statuses  []int
+		wantCalls int
+		wantErr   bool
+	}{
+		{
+			desc:      "success",
+			statuses:  []int{200},
+			wantCalls: 1,
+		},
+		{
+			desc:      "transient errors",
+			statuses:  []int{503, 429, 200},
+			wantCalls: 3,
+		},
+		{
+			desc:      "max attempts",
+			statuses:  []int{500},
+			wantCalls: 3,
+			wantErr:   true,
+		},
+		{
+			desc:      "not retryable",
+			statuses:  []int{400, 200},
+			wantCalls: 1,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			api, calls, done := newRetryTestAPI(tt.statuses...)
+			defer done()
+
+			var events []RetryEvent
+			api.Retry.OnRetry = func(e RetryEvent) {
+				events = append(events, e)
+			}
+
+			_, err := api.GetSelf(context.Background())
+			if got, want := err != nil, tt.wantErr; got != want {
+				t.Errorf("GetSelf error got %v want error %t", err, want)
+			}
+			if got, want := *calls, tt.wantCalls; got != want {
+				t.Errorf("Calls got %d want %d", got, want)
+			}
+			if got, want := len(events), tt.wantCalls-1; got != want {
+				t.Errorf("OnRetry calls got %d want %d", got, want)
+			}
+			for i, e := range events {
+				if got, want := e.Attempt, i+1; got != want {
+					t.Errorf("Event %d Attempt got %d want %d", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryContextDeadline(t *testing.T) {
+	api, calls, done := newRetryTestAPI(503, 200)
+	defer done()
+	api.Retry.BaseDelay = time.Hour
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	start := time.Now()
+	_, err := api.GetSelf(ctx)
+	if err == nil {
+		t.Fatalf("Want error")
+	}
+	if got, want := *calls, 1; got != want {
+		t.Errorf("Calls got %d want %d", got, want)
+	}
+	if time.Since(start) > 500*time.Millisecond {
+		t.Errorf("Waited for a retry that would exceed the deadline")
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	p := &RetryPolicy{
+		BaseDelay: time.Second,
+		MaxDelay:  5 * time.Second,
+	}
+	for attempt, want := range []time.Duration{0, 1, 2, 4, 5, 5} {
+		if attempt == 0 {
+			continue
+		}
+		if got := p.backoff(attempt); got != want*time.Second {
+			t.Errorf("Attempt %d got %s want %s", attempt, got, want*time.Second)
+		}
+	}
+
+	p.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if got := p.backoff(1); got < 500*time.Millisecond || got > time.Second {
+			t.Fatalf("Jittered delay %s out of range", got)
+		}
+	}
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestIsRetryable(t *testing.T) {
+	urlError := func(err error) error {
+		return &url.Error{Op: "Get", URL: "https://api.instagram.com/v1/users/self", Err: err}
+	}
+	tests := []struct {
+		desc string
+		err  error
+		want bool
+	}{
+		{
+			desc: "timeout",
+			err:  urlError(timeoutError{}),
+			want: true,
+		},
+		{
+			desc: "connection refused",
+			err:  urlError(&net.OpError{Op: "dial", Net: "tcp", Err: &os.SyscallError{Syscall: "connect", Err: syscall.ECONNREFUSED}}),
+			want: true,
+		},
+		{
+			desc: "connection reset",
+			err:  urlError(&net.OpError{Op: "read", Net: "tcp", Err: &os.SyscallError{Syscall: "read", Err: syscall.ECONNRESET}}),
+			want: true,
+		},
+		{
+			desc: "unexpected EOF",
+			err:  urlError(io.ErrUnexpectedEOF),
+			want: true,
+		},
+		{
+			desc: "unknown certificate authority",
+			err:  urlError(x509.UnknownAuthorityError{}),
+			want: false,
+		},
+		{
+			desc: "unsupported protocol scheme",
+			err:  urlError(errors.New(`unsupported protocol scheme "ftp"`)),
+			want: false,
+		},
+		{
+			desc: "rate limited",
+			err:  &MetaError{Code: 429, ErrorType: "OAuthRateLimitException"},
+			want: true,
+		},
+		{
+			desc: "not found",
+			err:  &MetaError{Code: 400, ErrorType: "APINotFoundError"},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if got := isRetryable(tt.err); got != tt.want {
+				t.Errorf("isRetryable got %t want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryPermanentNetworkError(t *testing.T) {
+	var retries int
+	api := &API{
+		BaseURL:     "ftp://api.instagram.com/v1",
+		AccessToken: "t0ken",
+		HTTPClient:  &http.Client{},
+		Retry: &RetryPolicy{
+			MaxAttempts: 3,
+			BaseDelay:   time.Millisecond,
+			OnRetry:     func(RetryEvent) { retries++ },
+		},
+	}
+	if _, err := api.GetSelf(context.Background()); err == nil {
+		t.Fatalf("Want error for unsupported scheme")
+	}
+	if got, want := retries, 0; got != want {
+		t.Errorf("Retries got %d want %d", got, want)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		header string
+		want   time.Duration
+		wantOK bool
+	}{
+		{"", 0, false},
+		{"7", 7 * time.Second, true},
+		{"soon", 0, false},
+		{"Wed, 21 Oct 2015 07:28:00 GMT", 0, true},
+	}
+	for _, tt := range tests {
+		resp := &Response{Header: http.Header{}}
+		resp.Header.Set("Retry-After", tt.header)
+		got, ok := retryAfter(resp)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("Retry-After %q got %s, %t want %s, %t", tt.header, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package instagram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// GetTag returns information about a tag. The name may include a leading #.
+// REST API: GET /tags/{tag-name}
+func (api *API) GetTag(ctx context.Context, name string) (res *TagResponse, err error) {
+	res = new(TagResponse)
+	path, err := tagPath(name)
+	if err != nil {
+		return
+	}
+	err = api.get(ctx, "GetTag", path, nil, res)
+	return
+}
+
+// GetTagRecentMedia returns the most recently tagged media. Use the
+// max_tag_id and min_tag_id params, from Pagination, to page backward or to
+// get only newer media.
+// REST API: GET /tags/{tag-name}/media/recent
+func (api *API) GetTagRecentMedia(ctx context.Context, name string, params url.Values) (res *PaginatedMediasResponse, err error) {
+	res = new(PaginatedMediasResponse)
+	path, err := tagPath(name)
+	if err != nil {
+		return
+	}
+	err = api.get(ctx, "GetTagRecentMedia", path+"/media/recent", params, res)
+	return
+}
+
+// SearchTags returns tags by name, with the exact match first.
+// REST API: GET /tags/search
+func (api *API) SearchTags(ctx context.Context, query string) (res *TagsResponse, err error) {
+	res = new(TagsResponse)
+	params := url.Values{}
+	params.Set("q", NormalizeTag(query))
+	err = api.get(ctx, "SearchTags", "/tags/search", params, res)
+	return
+}
+
+// NormalizeTag returns the canonical form of a tag name: without the
+// leading # or surrounding space, and lower case. Tags may contain any
+// Unicode letters.
+func NormalizeTag(name string) string {
+	name = strings.TrimSpace(name)
+	name = strings.TrimPrefix(name, "#")
+	return strings.ToLower(name)
+}
+
+func tagPath(name string) (string, error) {
+	tag := NormalizeTag(name)
+	if tag == "" {
+		return "", errors.New("instagram: tag name is empty")
+	}
+	return fmt.Sprintf("/tags/%s", url.PathEscape(tag)), nil
+}
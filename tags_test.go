@@ -0,0 +1,153 @@
+package instagram
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeTag(t *testing.T) {
+	tests := []struct {
+		name     string
+		want     string
+		wantPath string
+	}{
+		{"0219test", "0219test", "/tags/0219test"},
+		{" #GoLang ", "golang", "/tags/golang"},
+		{"#Café", "café", "/tags/caf%C3%A9"},
+		{"#日本", "日本", "/tags/%E6%97%A5%E6%9C%AC"},
+		{"a/b?c", "a/b?c", "/tags/a%2Fb%3Fc"},
+	}
+	for _, tt := range tests {
+		if got := NormalizeTag(tt.name); got != tt.want {
+			t.Errorf("NormalizeTag(%q) got %q want %q", tt.name, got, tt.want)
+		}
+		path, err := tagPath(tt.name)
+		if err != nil {
+			t.Errorf("tagPath(%q): %s", tt.name, err)
+		}
+		if path != tt.wantPath {
+			t.Errorf("tagPath(%q) got %q want %q", tt.name, path, tt.wantPath)
+		}
+	}
+
+	if _, err := tagPath(" # "); err == nil {
+		t.Errorf("Want error for empty tag")
+	}
+}
+
+func TestTags(t *testing.T) {
+	var requests []string
+	api, server := newTestAPIServer(t, func(r *http.Request) string {
+		requests = append(requests, r.URL.EscapedPath()+" q="+r.URL.Query().Get("q"))
+		switch r.URL.Path {
+		case "/v1/tags/search":
+			return "tags_search"
+		case "/v1/tags/café":
+			return "tag"
+		case "/v1/tags/café/media/recent":
+			return "tag_media"
+		}
+		return ""
+	})
+	defer server.Close()
+
+	ctx := context.Background()
+
+	tag, err := api.GetTag(ctx, "#Café")
+	if err != nil {
+		t.Fatalf("GetTag: %s", err)
+	}
+	if got, want := *tag.Tag, (Tag{Name: "0219test", MediaCount: 4}); got != want {
+		t.Errorf("Tag got %+v want %+v", got, want)
+	}
+
+	media, err := api.GetTagRecentMedia(ctx, "café", nil)
+	if err != nil {
+		t.Fatalf("GetTagRecentMedia: %s", err)
+	}
+	if got, want := len(media.Medias), 1; got != want {
+		t.Errorf("Medias got %d want %d", got, want)
+	}
+	p := media.Pagination
+	if p.NextMaxTagID != "1979320569926821011" || p.MinTagID != "1979320569926821011" {
+		t.Errorf("Tag pagination not decoded: %+v", p)
+	}
+
+	search, err := api.SearchTags(ctx, "#0219Test")
+	if err != nil {
+		t.Fatalf("SearchTags: %s", err)
+	}
+	if got, want := len(search.Tags), 2; got != want {
+		t.Errorf("Tags got %d want %d", got, want)
+	}
+
+	wantRequests := []string{
+		"/v1/tags/caf%C3%A9 q=",
+		"/v1/tags/caf%C3%A9/media/recent q=",
+		"/v1/tags/search q=0219test",
+	}
+	if !reflect.DeepEqual(requests, wantRequests) {
+		t.Errorf("Requests got %v want %v", requests, wantRequests)
+	}
+}
+
+func TestTagNextMedias(t *testing.T) {
+	tests := []struct {
+		name     string
+		wantPath string
+	}{
+		{"#Café", "/v1/tags/caf%C3%A9/media/recent"},
+		{"a/b?c", "/v1/tags/a%2Fb%3Fc/media/recent"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux, server := initTestServer()
+			defer server.Close()
+
+			api := &API{
+				AccessToken:          "t0ken",
+				ClientSecret:         "s1",
+				EnforceSignedRequest: true,
+				BaseURL:              server.URL + "/v1",
+				HTTPClient:           server.Client(),
+			}
+
+			var requests int
+			mux.HandleFunc("/v1/tags/", func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				if got := r.URL.EscapedPath(); got != tt.wantPath {
+					t.Errorf("Request %d path got %s want %s", requests, got, tt.wantPath)
+				}
+				params := r.URL.Query()
+				sig := params.Get("sig")
+				params.Del("sig")
+				path := strings.TrimPrefix(tt.wantPath, "/v1")
+				if want := signParams(path, params, "s1").Get("sig"); sig != want {
+					t.Errorf("Request %d sig got %s want %s", requests, sig, want)
+				}
+				if requests > 1 {
+					fmt.Fprint(w, `{"meta":{"code":200},"data":[]}`)
+					return
+				}
+				next := "https://api.instagram.com" + tt.wantPath + "?access_token=t0ken&max_tag_id=2&sig=x"
+				fmt.Fprintf(w, `{"meta":{"code":200},"data":[],"pagination":{"next_url":%q}}`, next)
+			})
+
+			ctx := context.Background()
+			res, err := api.GetTagRecentMedia(ctx, tt.name, nil)
+			if err != nil {
+				t.Fatalf("GetTagRecentMedia: %s", err)
+			}
+			if _, err := api.NextMedias(ctx, res.Pagination); err != nil {
+				t.Fatalf("NextMedias: %s", err)
+			}
+			if got, want := requests, 2; got != want {
+				t.Errorf("Requests got %d want %d", got, want)
+			}
+		})
+	}
+}
@@ -1,19 +1,45 @@
 package instagram
 
 import (
+	"encoding/json"
 	"fmt"
-	"io"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
-	"net/url"
-	"os"
 	"testing"
 )
 
 func newTestAPIServer(t *testing.T, fix func(*http.Request) string) (*API, *httptest.Server) {
-	httpClient, mux, server := initTestServer()
+	mux, server := initTestServer()
 
-	mux.HandleFunc("/v1/", func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("/v1/", fixtureHandler(t, fix))
+
+	api := &API{
+		EnforceSignedRequest: true,
+		BaseURL:              server.URL + "/v1",
+		HTTPClient:           server.Client(),
+	}
+
+	return api, server
+}
+
+func newTestGraphServer(t *testing.T, fix func(*http.Request) string) (*Graph, *httptest.Server) {
+	mux, server := initTestServer()
+
+	mux.HandleFunc("/", fixtureHandler(t, fix))
+
+	api := &API{
+		AccessToken:  "t0ken",
+		GraphBaseURL: server.URL,
+		HTTPClient:   server.Client(),
+	}
+
+	return NewGraph(api), server
+}
+
+// fixtureHandler responds with the fixture named by fix.
+func fixtureHandler(t *testing.T, fix func(*http.Request) string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
 		fixture := fix(r)
 		if fixture == "" {
 			t.Fatalf("no fixture for request: %s", r.URL)
@@ -21,41 +47,38 @@ func newTestAPIServer(t *testing.T, fix func(*http.Request) string) (*API, *http
 		}
 
 		path := fmt.Sprintf("testdata/%s.json", fixture)
-		f, err := os.Open(path)
+		body, err := ioutil.ReadFile(path)
 		if err != nil {
 			t.Fatalf("failed to open %s: %s", path, err)
 			return
 		}
-		defer f.Close()
 
-		w.Header().Set("Content-Type", "application/json")
-		io.Copy(w, f)
-	})
+		// Respond with the status code of the fixture's metadata, or
+		// 400 for a Graph API error.
+		var m struct {
+			Meta  *Meta
+			Error *GraphError
+		}
+		if err := json.Unmarshal(body, &m); err != nil {
+			t.Fatalf("failed to decode %s: %s", path, err)
+			return
+		}
 
-	api := &API{
-		EnforceSignedRequest: true,
-		HTTPClient:           httpClient,
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Ratelimit-Limit", "5000")
+		w.Header().Set("X-Ratelimit-Remaining", "4999")
+		switch {
+		case m.Meta != nil && m.Meta.Code != 0:
+			w.WriteHeader(m.Meta.Code)
+		case m.Error != nil:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		w.Write(body)
 	}
-
-	return api, server
 }
 
-func initTestServer() (*http.Client, *http.ServeMux, *httptest.Server) {
+func initTestServer() (*http.ServeMux, *httptest.Server) {
 	mux := http.NewServeMux()
 	server := httptest.NewServer(mux)
-	serverURL, _ := url.Parse(server.URL)
-	transport := &rewriteTransport{serverURL, &http.Transport{}}
-	client := &http.Client{Transport: transport}
-	return client, mux, server
-}
-
-type rewriteTransport struct {
-	serverURL *url.URL
-	Transport http.RoundTripper
-}
-
-func (t *rewriteTransport) RoundTrip(r *http.Request) (*http.Response, error) {
-	r.URL.Scheme = "http"
-	r.URL.Host = t.serverURL.Host
-	return t.Transport.RoundTrip(r)
+	return mux, server
 }
@@ -0,0 +1,167 @@
+package instagram
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// DefaultRefreshBefore is how long before expiry a RefreshingTokenSource
+// refreshes its token.
+const DefaultRefreshBefore = 7 * 24 * time.Hour
+
+// DefaultRefreshRetry is how long a RefreshingTokenSource waits after a
+// failed refresh before trying again.
+const DefaultRefreshRetry = 10 * time.Minute
+
+// Token is an access token.
+type Token struct {
+	AccessToken string
+	TokenType   string
+
+	// Expiry is when the token expires. Zero means it doesn't.
+	Expiry time.Time
+}
+
+// Expired returns whether the token is expired at now.
+func (t *Token) Expired(now time.Time) bool {
+	return !t.Expiry.IsZero() && !now.Before(t.Expiry)
+}
+
+// TokenSource supplies the access token of each request. Implementations
+// must be safe for concurrent use.
+type TokenSource interface {
+	Token(ctx context.Context) (*Token, error)
+}
+
+// RefreshingTokenSource is a TokenSource that refreshes a long-lived token
+// with OAuth.RefreshToken before it expires.
+type RefreshingTokenSource struct {
+	// OAuth refreshes the token.
+	OAuth OAuth
+
+	// RefreshBefore is how long before expiry the token is refreshed. The
+	// default is DefaultRefreshBefore.
+	RefreshBefore time.Duration
+
+	// RetryInterval is how long to wait after a failed refresh before
+	// trying again. The default is DefaultRefreshRetry.
+	RetryInterval time.Duration
+
+	// OnRefresh, if set, is called with each refreshed token, for instance
+	// to store it.
+	OnRefresh func(*Token)
+
+	mu         sync.Mutex
+	token      *Token
+	refreshing chan struct{} // closed when the refresh in progress is done
+	failedAt   time.Time
+	failure    error
+}
+
+// NewRefreshingTokenSource returns a RefreshingTokenSource for a long-lived
+// token, for instance from OAuth.ExchangeForLongLivedToken.
+func NewRefreshingTokenSource(o OAuth, t *Token) *RefreshingTokenSource {
+	return &RefreshingTokenSource{OAuth: o, token: t}
+}
+
+// Token returns the token, refreshing it first if it expires within
+// RefreshBefore. One caller refreshes at a time; while the token is valid,
+// other callers get it without waiting. If refreshing fails, the current
+// token is returned until it expires, and refreshing is tried again after
+// RetryInterval.
+func (s *RefreshingTokenSource) Token(ctx context.Context) (*Token, error) {
+	s.mu.Lock()
+	token := s.token
+	if token == nil {
+		s.mu.Unlock()
+		return nil, errors.New("instagram: no token to refresh")
+	}
+	now := time.Now()
+	switch {
+	case token.Expiry.IsZero() || token.Expiry.Sub(now) > s.refreshBefore():
+		s.mu.Unlock()
+		return token, nil
+	case s.refreshing != nil:
+		done := s.refreshing
+		s.mu.Unlock()
+		if !token.Expired(now) {
+			return token, nil
+		}
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		return s.Token(ctx)
+	case now.Sub(s.failedAt) < s.retryInterval():
+		failure := s.failure
+		s.mu.Unlock()
+		if token.Expired(now) {
+			return nil, failure
+		}
+		return token, nil
+	}
+	done := make(chan struct{})
+	s.refreshing = done
+	s.mu.Unlock()
+
+	t, err := s.OAuth.RefreshToken(ctx, token.AccessToken)
+
+	s.mu.Lock()
+	s.refreshing = nil
+	close(done)
+	if err != nil {
+		// A canceled caller says nothing about the token endpoint.
+		if ctx.Err() == nil {
+			s.failedAt = time.Now()
+			s.failure = err
+		}
+		s.mu.Unlock()
+		if token.Expired(time.Now()) {
+			return nil, err
+		}
+		s.OAuth.logger().WarnContext(ctx, "instagram: token refresh failed",
+			"expiry", token.Expiry,
+			"error", err)
+		return token, nil
+	}
+	s.token = t
+	s.failedAt = time.Time{}
+	s.failure = nil
+	s.mu.Unlock()
+
+	s.OAuth.logger().DebugContext(ctx, "instagram: token refreshed", "expiry", t.Expiry)
+	if s.OnRefresh != nil {
+		s.OnRefresh(t)
+	}
+	return t, nil
+}
+
+func (s *RefreshingTokenSource) retryInterval() time.Duration {
+	if s.RetryInterval == 0 {
+		return DefaultRefreshRetry
+	}
+	return s.RetryInterval
+}
+
+func (s *RefreshingTokenSource) refreshBefore() time.Duration {
+	if s.RefreshBefore == 0 {
+		return DefaultRefreshBefore
+	}
+	return s.RefreshBefore
+}
+
+// accessToken returns the access token of a request, from the TokenSource if
+// there is one.
+func (api *API) accessToken(ctx context.Context) (string, error) {
+	if api.TokenSource == nil {
+		return api.AccessToken, nil
+	}
+	t, err := api.TokenSource.Token(ctx)
+	if err != nil {
+		return "", err
+	}
+	return t.AccessToken, nil
+}
@@ -0,0 +1,283 @@
+package instagram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+// tokenServer counts the requests to refresh tokens. If block is set,
+// refreshes wait until it is closed.
+type tokenServer struct {
+	mu        sync.Mutex
+	attempts  int
+	refreshed int
+	block     chan struct{}
+}
+
+func (ts *tokenServer) counts() (attempts int, refreshed int) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	return ts.attempts, ts.refreshed
+}
+
+func newTestTokenServer(t *testing.T, ts *tokenServer) OAuth {
+	mux, server := initTestServer()
+	t.Cleanup(server.Close)
+
+	mux.HandleFunc("/access_token", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if got, want := q.Get("grant_type"), "ig_exchange_token"; got != want {
+			t.Errorf("grant_type got %q want %q", got, want)
+		}
+		if got, want := q.Get("client_secret"), "s1"; got != want {
+			t.Errorf("client_secret got %q want %q", got, want)
+		}
+		if q.Get("access_token") != "short" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error":{"message":"Invalid OAuth access token","type":"OAuthException","code":190}}`)
+			return
+		}
+		fmt.Fprint(w, `{"access_token":"long","token_type":"bearer","expires_in":5184000}`)
+	})
+	mux.HandleFunc("/refresh_access_token", func(w http.ResponseWriter, r *http.Request) {
+		if ts.block != nil {
+			<-ts.block
+		}
+		ts.mu.Lock()
+		defer ts.mu.Unlock()
+		ts.attempts++
+		q := r.URL.Query()
+		if got, want := q.Get("grant_type"), "ig_refresh_token"; got != want {
+			t.Errorf("grant_type got %q want %q", got, want)
+		}
+		if q.Get("access_token") != "long" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error":{"message":"Invalid OAuth access token","type":"OAuthException","code":190}}`)
+			return
+		}
+		ts.refreshed++
+		fmt.Fprint(w, `{"access_token":"long","token_type":"bearer","expires_in":5184000}`)
+	})
+
+	oauth := NewOAuth("c1", "s1", "http://localhost/")
+	oauth.GraphURL = server.URL
+	return oauth
+}
+
+func TestExchangeForLongLivedToken(t *testing.T) {
+	ts := &tokenServer{}
+	oauth := newTestTokenServer(t, ts)
+	ctx := context.Background()
+
+	token, err := oauth.ExchangeForLongLivedToken(ctx, "short")
+	if err != nil {
+		t.Fatalf("ExchangeForLongLivedToken: %s", err)
+	}
+	if got, want := token.AccessToken, "long"; got != want {
+		t.Errorf("AccessToken got %q want %q", got, want)
+	}
+	if got, want := time.Until(token.Expiry).Round(time.Hour), 60*24*time.Hour; got != want {
+		t.Errorf("Expires in got %s want %s", got, want)
+	}
+
+	token, err = oauth.RefreshToken(ctx, token.AccessToken)
+	if err != nil {
+		t.Fatalf("RefreshToken: %s", err)
+	}
+	if _, got := ts.counts(); got != 1 {
+		t.Errorf("Refreshed got %d want 1", got)
+	}
+
+	_, err = oauth.ExchangeForLongLivedToken(ctx, "bad")
+	if !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Error got %v want %v", err, ErrInvalidToken)
+	}
+}
+
+func TestRefreshingTokenSource(t *testing.T) {
+	tests := []struct {
+		desc          string
+		token         *Token
+		wantRefreshed int
+		wantErr       bool
+	}{
+		{
+			desc:  "not expiring",
+			token: &Token{AccessToken: "long", Expiry: time.Now().Add(30 * 24 * time.Hour)},
+		},
+		{
+			desc:  "no expiry",
+			token: &Token{AccessToken: "long"},
+		},
+		{
+			desc:          "expiring",
+			token:         &Token{AccessToken: "long", Expiry: time.Now().Add(24 * time.Hour)},
+			wantRefreshed: 1,
+		},
+		{
+			desc:  "refresh fails before expiry",
+			token: &Token{AccessToken: "revoked", Expiry: time.Now().Add(24 * time.Hour)},
+		},
+		{
+			desc:    "refresh fails after expiry",
+			token:   &Token{AccessToken: "revoked", Expiry: time.Now().Add(-time.Hour)},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			server := &tokenServer{}
+			ts := NewRefreshingTokenSource(newTestTokenServer(t, server), tt.token)
+			var stored *Token
+			ts.OnRefresh = func(t *Token) { stored = t }
+
+			token, err := ts.Token(context.Background())
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Token: %s", err)
+			}
+			if _, got := server.counts(); got != tt.wantRefreshed {
+				t.Errorf("Refreshed got %d want %d", got, tt.wantRefreshed)
+			}
+			if tt.wantRefreshed > 0 {
+				if stored != token {
+					t.Errorf("OnRefresh got %v want %v", stored, token)
+				}
+				if time.Until(token.Expiry) < ts.refreshBefore() {
+					t.Errorf("Expiry got %s, still within refresh", token.Expiry)
+				}
+			} else if token != tt.token {
+				t.Errorf("Token got %v want %v", token, tt.token)
+			}
+		})
+	}
+}
+
+func TestAPITokenSource(t *testing.T) {
+	ts := &tokenServer{}
+	oauth := newTestTokenServer(t, ts)
+	g, server := newTestGraphServer(t, func(r *http.Request) string {
+		if got, want := r.URL.Query().Get("access_token"), "long"; got != want {
+			t.Errorf("access_token got %q want %q", got, want)
+		}
+		return "graph_me"
+	})
+	defer server.Close()
+	g.api.AccessToken = ""
+	g.api.TokenSource = NewRefreshingTokenSource(oauth, &Token{
+		AccessToken: "long",
+		Expiry:      time.Now().Add(time.Hour),
+	})
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if _, err := g.GetMe(ctx); err != nil {
+			t.Fatalf("GetMe: %s", err)
+		}
+	}
+	if _, got := ts.counts(); got != 1 {
+		t.Errorf("Refreshed got %d want 1", got)
+	}
+}
+
+func TestRefreshingTokenSourceRetryInterval(t *testing.T) {
+	server := &tokenServer{}
+	token := &Token{AccessToken: "revoked", Expiry: time.Now().Add(24 * time.Hour)}
+	ts := NewRefreshingTokenSource(newTestTokenServer(t, server), token)
+	ts.RetryInterval = 50 * time.Millisecond
+
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got, err := ts.Token(ctx); err != nil || got != token {
+				t.Errorf("Token got %v, %v want %v", got, err, token)
+			}
+		}()
+	}
+	wg.Wait()
+	if got, _ := server.counts(); got != 1 {
+		t.Errorf("Attempts got %d want 1", got)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if _, err := ts.Token(ctx); err != nil {
+		t.Fatalf("Token: %s", err)
+	}
+	if got, _ := server.counts(); got != 2 {
+		t.Errorf("Attempts after retry interval got %d want 2", got)
+	}
+}
+
+func TestRefreshingTokenSourceDoesNotBlock(t *testing.T) {
+	server := &tokenServer{block: make(chan struct{})}
+	token := &Token{AccessToken: "long", Expiry: time.Now().Add(24 * time.Hour)}
+	ts := NewRefreshingTokenSource(newTestTokenServer(t, server), token)
+
+	ctx := context.Background()
+	refreshed := make(chan *Token)
+	go func() {
+		tok, _ := ts.Token(ctx)
+		refreshed <- tok
+	}()
+
+	// While the refresh is blocked, the valid token is returned.
+	deadline := time.Now().Add(time.Second)
+	for {
+		ts.mu.Lock()
+		started := ts.refreshing != nil
+		ts.mu.Unlock()
+		if started || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if got, err := ts.Token(ctx); err != nil || got != token {
+		t.Errorf("Token during refresh got %v, %v want %v", got, err, token)
+	}
+
+	close(server.block)
+	if got := <-refreshed; got == token {
+		t.Errorf("Want refreshed token")
+	}
+	if _, got := server.counts(); got != 1 {
+		t.Errorf("Refreshed got %d want 1", got)
+	}
+}
+
+func TestRefreshingTokenSourceExpired(t *testing.T) {
+	server := &tokenServer{block: make(chan struct{})}
+	token := &Token{AccessToken: "long", Expiry: time.Now().Add(-time.Minute)}
+	ts := NewRefreshingTokenSource(newTestTokenServer(t, server), token)
+
+	// Callers with an expired token wait for the one refresh.
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got, err := ts.Token(ctx); err != nil || got == token {
+				t.Errorf("Token got %v, %v want refreshed token", got, err)
+			}
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(server.block)
+	wg.Wait()
+	if got, _ := server.counts(); got != 1 {
+		t.Errorf("Attempts got %d want 1", got)
+	}
+}
@@ -23,6 +23,19 @@ type UserCounts struct {
 	FollowedBy int64 `json:"followed_by"`
 }
 
+// Relationship is the relationship between the authenticated user and
+// another user.
+type Relationship struct {
+	// OutgoingStatus is "follows", "requested" or "none".
+	OutgoingStatus string `json:"outgoing_status"`
+
+	// IncomingStatus is "followed_by", "requested_by",
+	// "blocked_by_you" or "none".
+	IncomingStatus string `json:"incoming_status"`
+
+	TargetUserIsPrivate bool `json:"target_user_is_private"`
+}
+
 // Media is the overall wrapper for any kind of Instagram media.
 type Media struct {
 	Type           string          `json:"type"`
@@ -98,6 +111,12 @@ type CarouselMedia struct {
 	UsersInPhoto []UserPosition `json:"users_in_photo"`
 }
 
+// Tag is a hashtag.
+type Tag struct {
+	Name       string `json:"name"`
+	MediaCount int64  `json:"media_count"`
+}
+
 // Location is the location of a media.
 type Location struct {
 	ID        string  `json:"id,omitempty"`